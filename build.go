@@ -71,6 +71,7 @@ var candidates = map[string][]string{
 		"common",
 		"parser",
 		"interpreter",
+		"cmd/krylisp",
 	},
 	"vet": {
 		"common",
@@ -78,6 +79,7 @@ var candidates = map[string][]string{
 		"parser",
 		"interpreter",
 		"types",
+		"cmd/krylisp",
 	},
 	"lint": {
 		"common",
@@ -85,6 +87,7 @@ var candidates = map[string][]string{
 		"types",
 		"parser",
 		"interpreter",
+		"cmd/krylisp",
 	},
 	"nilaway": {
 		"common",
@@ -0,0 +1,39 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/cmd/krylisp/01_repl_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 17. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-17 18:30:02 krylon>
+
+package main
+
+import "testing"
+
+func TestIncomplete(t *testing.T) {
+	type testCase struct {
+		src        string
+		incomplete bool
+	}
+
+	var cases = []testCase{
+		{src: "42"},
+		{src: "(+ 1 2)"},
+		{src: "(defun f (x)", incomplete: true},
+		{src: "(defun f (x)\n  (* x x))"},
+		{src: `(print "(")`},
+		{src: `(print "hello`, incomplete: true},
+		{src: `(print "a\"(")`},
+		{src: `(print "a\\")`},
+		{src: `(print "a\")`, incomplete: true},
+		{src: "(list 1 ; (\n 2)"},
+		{src: "(list 1 ; )\n", incomplete: true},
+	}
+
+	for _, c := range cases {
+		if res := incomplete(c.src); res != c.incomplete {
+			t.Errorf("incomplete(%q) returned %t (expected %t)",
+				c.src,
+				res,
+				c.incomplete)
+		}
+	}
+} // func TestIncomplete(t *testing.T)
@@ -0,0 +1,219 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/cmd/krylisp/main.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 17. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-17 18:12:40 krylon>
+
+// krylisp is the command line frontend to the interpreter. It can run
+// an interactive REPL, execute a script file, or evaluate an expression
+// given on the command line.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/blicero/krylisp/common"
+	"github.com/blicero/krylisp/interpreter"
+	"github.com/blicero/krylisp/parser"
+	"github.com/hashicorp/logutils"
+)
+
+// Exit codes returned by the program.
+// exitUsage is the same code the flag package uses for invalid flags.
+const (
+	exitOK         = 0
+	exitEval       = 1
+	exitUsage      = 2
+	exitParse      = 3
+	exitIO         = 4
+	defaultLogLvl  = "SILENT"
+	scriptSuffix   = ".kl"
+	historyFileFmt = "%s.history"
+)
+
+// errParse wraps errors that occurred while reading source code, so we can
+// tell them apart from errors that occurred during evaluation.
+type errParse struct {
+	err error
+}
+
+func (e errParse) Error() string { return e.err.Error() }
+func (e errParse) Unwrap() error { return e.err }
+
+func main() {
+	var (
+		err      error
+		expr     string
+		haveExpr bool
+		logLevel string
+		profile  string
+		in       *interpreter.Interpreter
+		lvls     = make([]string, len(common.LogLevels))
+	)
+
+	for i, l := range common.LogLevels {
+		lvls[i] = string(l)
+	}
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(),
+			`Usage: %s [options] [script%s]
+
+Without a script or an expression, %s starts an interactive REPL.
+
+Options:
+`,
+			os.Args[0],
+			scriptSuffix,
+			common.AppName)
+		flag.PrintDefaults()
+	}
+
+	flag.StringVar(&expr, "e", "", "Evaluate the given expression(s), print the result and exit")
+	flag.StringVar(&profile, "profile", "full",
+		fmt.Sprintf("The capability profile of the interpreter, one of %s",
+			strings.Join(interpreter.Profiles(), ", ")))
+	flag.StringVar(&logLevel, "loglevel", defaultLogLvl,
+		fmt.Sprintf(`Log messages with a lower priority than this will be discarded.
+Valid log levels are: %s
+This flag is not case-sensitive.`, strings.Join(lvls, ", ")))
+
+	flag.Parse()
+
+	// An empty expression is still an expression, it evaluates to NIL
+	// rather than starting the REPL.
+	flag.Visit(func(f *flag.Flag) {
+		haveExpr = haveExpr || f.Name == "e"
+	})
+
+	var lvl = logutils.LogLevel(strings.ToUpper(logLevel))
+
+	if !validLogLevel(lvl) {
+		fmt.Fprintf(os.Stderr, "Invalid log level: %s\n", logLevel)
+		os.Exit(exitUsage)
+	}
+
+	common.SetLogLevel(lvl)
+
+	if !slices.Contains(interpreter.Profiles(), profile) {
+		fmt.Fprintf(os.Stderr, "Invalid capability profile: %s\n", profile)
+		os.Exit(exitUsage)
+	}
+
+	if haveExpr && flag.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "Cannot use -e and a script at the same time")
+		flag.Usage()
+		os.Exit(exitUsage)
+	} else if flag.NArg() > 1 {
+		fmt.Fprintln(os.Stderr, "Too many arguments")
+		flag.Usage()
+		os.Exit(exitUsage)
+	}
+
+	if in, err = interpreter.MakeSandbox(profile, false); err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot create Interpreter: %s\n",
+			err.Error())
+		os.Exit(exitIO)
+	}
+
+	switch {
+	case haveExpr:
+		var res parser.LispValue
+
+		if res, err = evalString(in, "<expr>", expr); err == nil {
+			fmt.Println(repr(res))
+		}
+	case flag.NArg() == 1:
+		err = runScript(in, flag.Arg(0))
+	default:
+		err = repl(in)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	os.Exit(exitOK)
+} // func main()
+
+func validLogLevel(lvl logutils.LogLevel) bool {
+	for _, l := range common.LogLevels {
+		if l == lvl {
+			return true
+		}
+	}
+
+	return false
+} // func validLogLevel(lvl logutils.LogLevel) bool
+
+func exitCode(err error) int {
+	var (
+		pe errParse
+		pa *os.PathError
+	)
+
+	switch {
+	case errors.As(err, &pe):
+		return exitParse
+	case errors.As(err, &pa):
+		return exitIO
+	default:
+		return exitEval
+	}
+} // func exitCode(err error) int
+
+// evalString parses all top-level forms in src and evaluates them in order.
+// It returns the value of the last form.
+func evalString(in *interpreter.Interpreter, filename, src string) (parser.LispValue, error) {
+	var (
+		err  error
+		prog *parser.Program
+		res  parser.LispValue
+		par  = parser.NewProgram()
+	)
+
+	if prog, err = par.ParseString(filename, src); err != nil {
+		return nil, errParse{err: err}
+	}
+
+	for _, form := range prog.Forms {
+		if res, err = in.Eval(form); err != nil {
+			return nil, fmt.Errorf("Error evaluating %s: %w",
+				form,
+				err)
+		}
+	}
+
+	return res, nil
+} // func evalString(in *interpreter.Interpreter, filename, src string) (parser.LispValue, error)
+
+// runScript loads the file at path and evaluates its content.
+func runScript(in *interpreter.Interpreter, path string) error {
+	var (
+		err error
+		src []byte
+	)
+
+	if src, err = os.ReadFile(path); err != nil {
+		return err
+	}
+
+	_, err = evalString(in, path, string(src))
+	return err
+} // func runScript(in *interpreter.Interpreter, path string) error
+
+// repr returns the printed representation of a value as displayed to the
+// user.
+func repr(v parser.LispValue) string {
+	if v == nil {
+		return "NIL"
+	}
+
+	return v.String()
+} // func repr(v parser.LispValue) string
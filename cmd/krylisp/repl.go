@@ -0,0 +1,159 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/cmd/krylisp/repl.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 17. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-17 18:12:51 krylon>
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blicero/krylisp/common"
+	"github.com/blicero/krylisp/interpreter"
+	"github.com/blicero/krylisp/parser"
+	"github.com/peterh/liner"
+)
+
+const (
+	prompt     = "krylisp> "
+	contPrompt = "   ...> "
+)
+
+// repl runs the interactive read-eval-print loop until the user closes
+// the input stream (Ctrl-D). Errors in the evaluated expressions are
+// reported, but do not terminate the loop.
+func repl(in *interpreter.Interpreter) error {
+	var (
+		err     error
+		line    = liner.NewLiner()
+		histDir = filepath.Join(common.BaseDir,
+			fmt.Sprintf(historyFileFmt, strings.ToLower(common.AppName)))
+	)
+
+	defer line.Close() // nolint: errcheck
+
+	line.SetCtrlCAborts(true)
+	line.SetMultiLineMode(true)
+
+	if fh, ferr := os.Open(histDir); ferr == nil {
+		line.ReadHistory(fh) // nolint: errcheck
+		fh.Close()           // nolint: errcheck
+	}
+
+	defer saveHistory(line, histDir)
+
+	var buf strings.Builder
+
+	for {
+		var (
+			input string
+			p     = prompt
+		)
+
+		if buf.Len() > 0 {
+			p = contPrompt
+		}
+
+		if input, err = line.Prompt(p); err != nil {
+			if errors.Is(err, liner.ErrPromptAborted) {
+				buf.Reset()
+				continue
+			} else if errors.Is(err, io.EOF) {
+				fmt.Println()
+				return nil
+			}
+
+			return err
+		}
+
+		buf.WriteString(input)
+		buf.WriteString("\n")
+
+		if incomplete(buf.String()) {
+			continue
+		}
+
+		var src = strings.TrimSpace(buf.String())
+		buf.Reset()
+
+		if src == "" {
+			continue
+		}
+
+		line.AppendHistory(src)
+
+		var res parser.LispValue
+
+		if res, err = evalString(in, "<stdin>", src); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+			continue
+		}
+
+		fmt.Println(repr(res))
+	}
+} // func repl(in *interpreter.Interpreter) error
+
+func saveHistory(line *liner.State, path string) {
+	var (
+		err error
+		fh  *os.File
+	)
+
+	if fh, err = os.Create(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot save history to %s: %s\n",
+			path,
+			err.Error())
+		return
+	}
+
+	defer fh.Close() // nolint: errcheck
+
+	if _, err = line.WriteHistory(fh); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing history to %s: %s\n",
+			path,
+			err.Error())
+	}
+} // func saveHistory(line *liner.State, path string)
+
+// incomplete returns true if src contains unbalanced opening parentheses
+// or an unterminated string literal. Parentheses within strings or comments
+// are ignored, as are quotes escaped with a backslash within strings. The
+// REPL uses it to decide if it needs to read more lines before an
+// expression is complete.
+func incomplete(src string) bool {
+	var (
+		depth     int
+		inString  bool
+		inComment bool
+		escaped   bool
+	)
+
+	for _, c := range src {
+		switch {
+		case inComment:
+			inComment = c != '\n'
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case inString:
+			inString = c != '"'
+		case c == ';':
+			inComment = true
+		case c == '"':
+			inString = true
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		}
+	}
+
+	return depth > 0 || inString
+} // func incomplete(src string) bool
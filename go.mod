@@ -9,4 +9,12 @@ require (
 	github.com/hashicorp/logutils v1.0.0
 )
 
-require github.com/davecgh/go-spew v1.1.1
+require (
+	github.com/davecgh/go-spew v1.1.1
+	github.com/peterh/liner v1.2.2
+)
+
+require (
+	github.com/mattn/go-runewidth v0.0.3 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+)
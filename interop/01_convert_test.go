@@ -0,0 +1,185 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interop/01_convert_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 03:20:45 krylon>
+
+package interop
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/blicero/krylisp/interpreter"
+	"github.com/blicero/krylisp/parser"
+)
+
+type point struct {
+	X, Y    int
+	Label   string `sexp:"name"`
+	Ignored string `sexp:"-"`
+	hidden  int
+}
+
+type server struct {
+	ListenAddr string
+}
+
+func TestToLisp(t *testing.T) {
+	var (
+		seven = 7
+		nilp  *int
+	)
+
+	type testCase struct {
+		val         any
+		expected    string
+		expectError bool
+	}
+
+	var cases = []testCase{
+		{val: nil, expected: "NIL"},
+		{val: true, expected: "T"},
+		{val: false, expected: "NIL"},
+		{val: 42, expected: "42"},
+		{val: int8(-8), expected: "-8"},
+		{val: uint16(16), expected: "16"},
+		{val: 2.5, expected: "2.5"},
+		{val: float32(1), expected: "1.0"},
+		{val: "text", expected: `"text"`},
+		{val: []int{1, 2, 3}, expected: "(1 2 3)"},
+		{val: []string{}, expected: "NIL"},
+		{val: [2]bool{true, false}, expected: "(T NIL)"},
+		{val: [][]int{{1}, {2, 3}}, expected: "((1) (2 3))"},
+		{val: map[string]int{"b": 2, "a": 1}, expected: `(("a" 1) ("b" 2))`},
+		{val: point{X: 1, Y: 2, Label: "p", Ignored: "x", hidden: 3}, expected: `(:X 1 :Y 2 :NAME "p")`},
+		{val: server{ListenAddr: ":80"}, expected: `(:LISTEN-ADDR ":80")`},
+		{val: &seven, expected: "7"},
+		{val: nilp, expected: "NIL"},
+		{val: []any{1, "a", nil}, expected: `(1 "a" NIL)`},
+		{val: parser.Symbol{Sym: "FOO"}, expected: "FOO"},
+		{val: errors.New("boom"), expected: `#<SIMPLE-ERROR "boom">`},
+		{val: uint64(math.MaxUint64), expectError: true},
+		{val: make(chan int), expectError: true},
+		{val: func() {}, expectError: true},
+	}
+
+	for _, c := range cases {
+		var res, err = ToLisp(c.val)
+
+		if err != nil {
+			if !c.expectError {
+				t.Errorf("Error converting %#v: %s", c.val, err.Error())
+			} else if !errors.Is(err, interpreter.ErrType) {
+				t.Errorf("Error converting %#v is not an ErrType: %s", c.val, err.Error())
+			}
+		} else if c.expectError {
+			t.Errorf("Converting %#v should have failed, but returned %s", c.val, res)
+		} else if res.String() != c.expected {
+			t.Errorf("Unexpected result converting %#v: expected %s, got %s",
+				c.val,
+				c.expected,
+				res)
+		}
+	}
+} // func TestToLisp(t *testing.T)
+
+func TestFromLisp(t *testing.T) {
+	var (
+		i   int
+		i8  int8
+		u   uint
+		f   float64
+		s   string
+		b   bool
+		a   any
+		lv  parser.LispValue
+		ip  *int
+		sl  []int
+		arr [2]string
+		m   map[string]int
+		p   point
+		srv server
+	)
+
+	type testCase struct {
+		src         string
+		target      any
+		expected    any
+		expectError bool
+	}
+
+	var cases = []testCase{
+		{src: "42", target: &i, expected: 42},
+		{src: "-128", target: &i8, expected: int8(-128)},
+		{src: "128", target: &i8, expectError: true},
+		{src: "-1", target: &u, expectError: true},
+		{src: "7", target: &u, expected: uint(7)},
+		{src: "2.5", target: &f, expected: 2.5},
+		{src: "3", target: &f, expected: 3.0},
+		{src: "3.5", target: &i, expectError: true},
+		{src: `"abc"`, target: &s, expected: "abc"},
+		{src: "abc", target: &s, expectError: true},
+		{src: "t", target: &b, expected: true},
+		{src: "nil", target: &b, expected: false},
+		{src: "(1 2)", target: &a, expected: []any{int64(1), int64(2)}},
+		{src: `"x"`, target: &a, expected: "x"},
+		{src: "nil", target: &a, expected: nil},
+		{src: "(1 2)", target: &lv, expected: parser.MakeList(parser.Integer{Int: 1}, parser.Integer{Int: 2})},
+		{src: "5", target: &ip, expected: func() *int { var x = 5; return &x }()},
+		{src: "nil", target: &ip, expected: (*int)(nil)},
+		{src: "(1 2 3)", target: &sl, expected: []int{1, 2, 3}},
+		{src: "nil", target: &sl, expected: []int(nil)},
+		{src: `(1 "a")`, target: &sl, expectError: true},
+		{src: `("a" "b")`, target: &arr, expected: [2]string{"a", "b"}},
+		{src: `("a")`, target: &arr, expectError: true},
+		{src: `(("a" 1) ("b" 2))`, target: &m, expected: map[string]int{"a": 1, "b": 2}},
+		{src: `(("a" 1 2))`, target: &m, expectError: true},
+		{src: `(:x 1 :name "p")`, target: &p, expected: point{X: 1, Label: "p"}},
+		{src: `(:x 1 :z 2)`, target: &p, expectError: true},
+		{src: `(:listen-addr ":80")`, target: &srv, expected: server{ListenAddr: ":80"}},
+		{src: `(:x 1 :y)`, target: &p, expectError: true},
+		{src: `(:ignored "x")`, target: &p, expectError: true},
+	}
+
+	var par = parser.New()
+
+	for _, c := range cases {
+		var (
+			err error
+			val *parser.LispValue
+		)
+
+		if val, err = par.ParseString("test", c.src); err != nil {
+			t.Fatalf("Cannot parse %q: %s", c.src, err.Error())
+		} else if err = FromLisp(*val, c.target); err != nil {
+			if !c.expectError {
+				t.Errorf("Error converting %s to %T: %s", c.src, c.target, err.Error())
+			}
+			continue
+		} else if c.expectError {
+			t.Errorf("Converting %s to %T should have failed", c.src, c.target)
+			continue
+		}
+
+		var res = reflect.ValueOf(c.target).Elem().Interface()
+
+		if lres, ok := res.(parser.LispValue); ok {
+			if !lres.Equal(c.expected.(parser.LispValue)) {
+				t.Errorf("Converting %s returned %s, expected %s", c.src, lres, c.expected)
+			}
+		} else if !reflect.DeepEqual(res, c.expected) {
+			t.Errorf("Converting %s to %T returned %#v, expected %#v",
+				c.src,
+				c.target,
+				res,
+				c.expected)
+		}
+	}
+
+	if err := FromLisp(parser.Integer{Int: 1}, i); err == nil {
+		t.Error("FromLisp should reject a target that is not a pointer")
+	}
+} // func TestFromLisp(t *testing.T)
@@ -0,0 +1,128 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interop/02_func_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 03:36:02 krylon>
+
+package interop
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/blicero/krylisp/interpreter"
+	"github.com/blicero/krylisp/parser"
+)
+
+var errNegative = errors.New("negative number")
+
+func TestRegister(t *testing.T) {
+	var (
+		err error
+		in  *interpreter.Interpreter
+		par = parser.New()
+	)
+
+	if in, err = interpreter.MakeInterpreter(nil, false); err != nil {
+		t.Fatalf("Cannot create Interpreter: %s", err.Error())
+	}
+
+	var funcs = map[string]any{
+		"go-add":   func(a, b int) int { return a + b },
+		"go-upper": strings.ToUpper,
+		"go-join":  func(sep string, parts ...string) string { return strings.Join(parts, sep) },
+		"go-half": func(x float64) (float64, error) {
+			if x < 0 {
+				return 0, errNegative
+			}
+			return x / 2, nil
+		},
+		"go-divmod": func(a, b int) (int, int) { return a / b, a % b },
+		"go-point":  func(x, y int) point { return point{X: x, Y: y, Label: "p"} },
+		"go-norm":   func(p point) int { return p.X*p.X + p.Y*p.Y },
+		"go-count":  func(m map[string]int) int { return len(m) },
+		"go-nop":    func() {},
+		"go-fail":   func() error { return errNegative },
+		"go-ok":     func() error { return nil },
+		"go-panic":  func() int { panic("oops") },
+		"go-any":    func(x any) string { return fmt.Sprintf("%T", x) },
+	}
+
+	for name, fn := range funcs {
+		if err = Register(in, name, fn); err != nil {
+			t.Fatalf("Cannot register %s: %s", name, err.Error())
+		}
+	}
+
+	type testCase struct {
+		expr        string
+		result      string
+		expectError bool
+	}
+
+	var cases = []testCase{
+		{expr: "(go-add 1 2)", result: "3"},
+		{expr: `(go-upper "abc")`, result: `"ABC"`},
+		{expr: `(go-join "-")`, result: `""`},
+		{expr: `(go-join "-" "a" "b" "c")`, result: `"a-b-c"`},
+		{expr: "(go-half 8)", result: "4.0"},
+		{expr: "(go-divmod 7 2)", result: "(3 1)"},
+		{expr: "(go-point 1 2)", result: `(:X 1 :Y 2 :NAME "p")`},
+		{expr: "(go-norm (go-point 3 4))", result: "25"},
+		{expr: `(go-count '(("a" 1) ("b" 2)))`, result: "2"},
+		{expr: "(go-nop)", result: "NIL"},
+		{expr: "(go-ok)", result: "NIL"},
+		{expr: "(go-any 1)", result: `"int64"`},
+		{expr: `(handler-case (go-half -1) (error (c) (condition-type c)))`, result: "SIMPLE-ERROR"},
+		{expr: `(handler-case (go-add 1 "a") (type-error (c) (condition-type c)))`, result: "TYPE-ERROR"},
+		{expr: "(go-add 1)", expectError: true},
+		{expr: "(go-add 1 2 3)", expectError: true},
+		{expr: `(go-join "-" 1)`, expectError: true},
+		{expr: "(go-fail)", expectError: true},
+		{expr: "(go-panic)", expectError: true},
+	}
+
+	for _, c := range cases {
+		var (
+			form *parser.LispValue
+			res  parser.LispValue
+		)
+
+		if form, err = par.ParseString("test", c.expr); err != nil {
+			t.Fatalf("Cannot parse %q: %s", c.expr, err.Error())
+		} else if res, err = in.Eval(*form); err != nil {
+			if !c.expectError {
+				t.Errorf("Error evaluating %q: %s", c.expr, err.Error())
+			}
+		} else if c.expectError {
+			t.Errorf("Evaluating %q should have failed, but returned %s", c.expr, res)
+		} else if res.String() != c.result {
+			t.Errorf("Unexpected result for %q: expected %s, got %s",
+				c.expr,
+				c.result,
+				res)
+		}
+	}
+
+	var form, _ = par.ParseString("test", "(go-half -4)")
+
+	if _, err = in.Eval(*form); !errors.Is(err, errNegative) {
+		t.Errorf("Error returned by Go function was not passed on: %v", err)
+	}
+
+	form, _ = par.ParseString("test", "(go-add 1)")
+
+	if _, err = in.Eval(*form); !errors.Is(err, ErrArity) {
+		t.Errorf("Wrong number of arguments was not reported as ErrArity: %v", err)
+	}
+
+	if err = Register(in, "not-a-func", 42); err == nil {
+		t.Error("Registering a non-function should have failed")
+	} else if err = Register(in, "nil-func", (func())(nil)); err == nil {
+		t.Error("Registering a nil function should have failed")
+	} else if _, err = Wrap(nil); err == nil {
+		t.Error("Wrapping nil should have failed")
+	}
+} // func TestRegister(t *testing.T)
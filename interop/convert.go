@@ -0,0 +1,75 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interop/convert.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 02:41:26 krylon>
+
+// Package interop converts between Go values and LispValues, and makes
+// arbitrary Go functions callable from Lisp.
+//
+// Values are converted by the sexp package, see there for the details and
+// for the struct tags that rename or skip fields. Beyond that, Go values
+// that implement error are converted to Conditions, and converting a
+// property list to a struct fails if it names a field the struct does not
+// have. LispValues are passed through unchanged in both directions. All
+// conversion errors wrap interpreter.ErrType.
+package interop
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/blicero/krylisp/interpreter"
+	"github.com/blicero/krylisp/parser"
+	"github.com/blicero/krylisp/sexp"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+var converter = sexp.Converter{
+	Error: func(err error) parser.LispValue {
+		return interpreter.AsCondition(err)
+	},
+	DisallowUnknownFields: true,
+}
+
+// ToLisp converts a Go value to a LispValue.
+func ToLisp(v any) (parser.LispValue, error) {
+	var val, err = converter.Marshal(v)
+
+	return val, typeError(err)
+} // func ToLisp(v any) (parser.LispValue, error)
+
+// FromLisp converts a LispValue to a Go value and stores it in the value
+// target points to, which must be a non-nil pointer.
+func FromLisp(v parser.LispValue, target any) error {
+	var rv = reflect.ValueOf(target)
+
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("Target must be a non-nil pointer, not %T", target)
+	}
+
+	return typeError(converter.Unmarshal(v, target))
+} // func FromLisp(v parser.LispValue, target any) error
+
+// Convert converts a LispValue to a Go value of type t.
+func Convert(v parser.LispValue, t reflect.Type) (reflect.Value, error) {
+	var ptr = reflect.New(t)
+
+	if err := converter.Unmarshal(v, ptr.Interface()); err != nil {
+		return reflect.Value{}, typeError(err)
+	}
+
+	return ptr.Elem(), nil
+} // func Convert(v parser.LispValue, t reflect.Type) (reflect.Value, error)
+
+// typeError makes err, an error returned by the sexp package, wrap
+// interpreter.ErrType, so it is raised as a type error in Lisp.
+func typeError(err error) error {
+	if err == nil || errors.Is(err, interpreter.ErrType) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", interpreter.ErrType, err)
+} // func typeError(err error) error
@@ -0,0 +1,136 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interop/func.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 03:02:11 krylon>
+
+package interop
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/blicero/krylisp/interpreter"
+	"github.com/blicero/krylisp/parser"
+)
+
+// ErrArity indicates a call to a Go function with the wrong number of
+// arguments.
+var ErrArity = errors.New("Wrong number of arguments")
+
+// errPanic is used to report a panic in a Go function called from Lisp.
+var errPanic = errors.New("Go function panicked")
+
+// Wrap creates a BuiltinFunc that calls the Go function fn via reflection.
+// The arguments are converted to the parameter types of fn, see Convert.
+// If the last result of fn is an error, a non-nil error is returned as the
+// error of the call, so it is raised as a Lisp error. Of the remaining
+// results, none is returned as NIL, one is converted with ToLisp, several
+// are returned as a List.
+func Wrap(fn any) (interpreter.BuiltinFunc, error) {
+	var rv = reflect.ValueOf(fn)
+
+	if !rv.IsValid() {
+		return nil, errors.New("Cannot wrap nil, it is not a function")
+	} else if rv.Kind() != reflect.Func {
+		return nil, fmt.Errorf("Cannot wrap a %T, it is not a function", fn)
+	} else if rv.IsNil() {
+		return nil, errors.New("Cannot wrap a nil function")
+	}
+
+	var (
+		t        = rv.Type()
+		minArgs  = t.NumIn()
+		maxArgs  = t.NumIn()
+		nresults = t.NumOut()
+		hasErr   = nresults > 0 && t.Out(nresults-1) == errorType
+	)
+
+	if t.IsVariadic() {
+		minArgs--
+		maxArgs = -1
+	}
+
+	if hasErr {
+		nresults--
+	}
+
+	var wrapped = func(args []parser.LispValue) (res parser.LispValue, err error) {
+		if len(args) < minArgs || (maxArgs != -1 && len(args) > maxArgs) {
+			return nil, fmt.Errorf("%w: got %d, want %s",
+				ErrArity,
+				len(args),
+				arity(minArgs, maxArgs))
+		}
+
+		var in = make([]reflect.Value, len(args))
+
+		for i, arg := range args {
+			var pt reflect.Type
+
+			if t.IsVariadic() && i >= t.NumIn()-1 {
+				pt = t.In(t.NumIn() - 1).Elem()
+			} else {
+				pt = t.In(i)
+			}
+
+			if in[i], err = Convert(arg, pt); err != nil {
+				return nil, fmt.Errorf("Argument %d: %w", i+1, err)
+			}
+		}
+
+		defer func() {
+			if x := recover(); x != nil {
+				res = nil
+				err = fmt.Errorf("%w: %v", errPanic, x)
+			}
+		}()
+
+		var out = rv.Call(in)
+
+		if hasErr && !out[nresults].IsNil() {
+			return nil, out[nresults].Interface().(error)
+		}
+
+		switch nresults {
+		case 0:
+			return parser.MakeSymbol("NIL"), nil
+		case 1:
+			return ToLisp(out[0].Interface())
+		default:
+			var vals = make([]parser.LispValue, nresults)
+
+			for i := range vals {
+				if vals[i], err = ToLisp(out[i].Interface()); err != nil {
+					return nil, fmt.Errorf("Result %d: %w", i+1, err)
+				}
+			}
+
+			return parser.MakeList(vals...), nil
+		}
+	}
+
+	return wrapped, nil
+} // func Wrap(fn any) (interpreter.BuiltinFunc, error)
+
+// Register wraps the Go function fn and registers it with the Interpreter
+// under the given name.
+func Register(in *interpreter.Interpreter, name string, fn any) error {
+	var wrapped, err = Wrap(fn)
+
+	if err != nil {
+		return fmt.Errorf("Cannot register %s: %w", name, err)
+	}
+
+	return in.RegisterBuiltin(name, wrapped)
+} // func Register(in *interpreter.Interpreter, name string, fn any) error
+
+// arity describes the number of arguments accepted, for error messages.
+func arity(lo, hi int) string {
+	if hi == -1 {
+		return fmt.Sprintf("at least %d", lo)
+	}
+
+	return fmt.Sprintf("exactly %d", lo)
+} // func arity(lo, hi int) string
@@ -9,6 +9,7 @@ package interpreter
 import (
 	"testing"
 
+	"github.com/alecthomas/participle/v2/lexer"
 	"github.com/blicero/krylisp/parser"
 )
 
@@ -19,12 +20,12 @@ func TestLookupSimple(t *testing.T) {
 		expectedValue parser.LispValue
 	}
 
-	var env = environment{
+	var env = Environment{
 		scope: &scope{
-			bindings: map[parser.Symbol]parser.LispValue{
-				sym("zero"): parser.Integer{Int: 0},
-				sym("name"): parser.String{Str: "Odysseus"},
-				sym("age"):  parser.Integer{Int: 42},
+			bindings: map[*parser.Atom]parser.LispValue{
+				sym("zero").Atom(): parser.Integer{Int: 0},
+				sym("name").Atom(): parser.String{Str: "Odysseus"},
+				sym("age").Atom():  parser.Integer{Int: 42},
 			},
 		},
 	}
@@ -57,17 +58,17 @@ func TestLookupSimple(t *testing.T) {
 } // func TestLookupSimple(t *testing.T)
 
 func TestLookupScoped(t *testing.T) {
-	var env = environment{
+	var env = Environment{
 		scope: &scope{
-			bindings: map[parser.Symbol]parser.LispValue{
-				sym("y"):    parser.Integer{Int: 23},
-				sym("temp"): parser.Integer{Int: 109},
+			bindings: map[*parser.Atom]parser.LispValue{
+				sym("y").Atom():    parser.Integer{Int: 23},
+				sym("temp").Atom(): parser.Integer{Int: 109},
 			},
 			parent: &scope{
-				bindings: map[parser.Symbol]parser.LispValue{
-					sym("x"):      parser.Integer{Int: 10},
-					sym("temp"):   parser.Integer{Int: 42},
-					sym("lambda"): parser.Integer{Int: 83},
+				bindings: map[*parser.Atom]parser.LispValue{
+					sym("x").Atom():      parser.Integer{Int: 10},
+					sym("temp").Atom():   parser.Integer{Int: 42},
+					sym("lambda").Atom(): parser.Integer{Int: 83},
 				},
 			},
 		},
@@ -120,3 +121,23 @@ func TestLookupScoped(t *testing.T) {
 		}
 	}
 } // func TestLookupScoped(t *testing.T)
+
+func TestLookupPosition(t *testing.T) {
+	var (
+		env = MakeEnvironment()
+		def = parser.Symbol{Pos: lexer.Position{Line: 1, Column: 8}, Sym: "X"}
+		use = parser.Symbol{Pos: lexer.Position{Line: 3, Column: 2}, Sym: "X"}
+	)
+
+	env.Set(def, parser.Integer{Int: 1})
+
+	if val, ok := env.Lookup(use); !ok {
+		t.Errorf("Lookup of %s at line 3 did not find the binding made at line 1", use)
+	} else if !val.Equal(parser.Integer{Int: 1}) {
+		t.Errorf("Lookup of %s returned %s, expected 1", use, val)
+	} else if err := env.Assign(use, parser.Integer{Int: 2}); err != nil {
+		t.Errorf("Cannot assign %s: %s", use, err.Error())
+	} else if val, _ = env.Lookup(sym("x")); !val.Equal(parser.Integer{Int: 2}) {
+		t.Errorf("Assignment through %s was not visible, X is %s", use, val)
+	}
+} // func TestLookupPosition(t *testing.T)
@@ -16,11 +16,11 @@ import (
 )
 
 var in = Interpreter{
-	Env: &environment{
+	Env: &Environment{
 		scope: &scope{
-			bindings: map[parser.Symbol]parser.LispValue{
-				sym("karl"):       parser.String{Str: "Otto"},
-				sym("the-answer"): parser.Integer{Int: 42},
+			bindings: map[*parser.Atom]parser.LispValue{
+				sym("karl").Atom():       parser.String{Str: "Otto"},
+				sym("the-answer").Atom(): parser.Integer{Int: 42},
 			},
 		},
 	},
@@ -34,8 +34,78 @@ func init() {
 		panic(fmt.Errorf("Failed to create Logger for Interpreter: %s",
 			err.Error()))
 	}
+
+	in.defineFunctions()
 }
 
+var par = parser.New()
+
+// read parses a single expression from src.
+func read(src string) (parser.LispValue, error) {
+	var (
+		err error
+		val *parser.LispValue
+	)
+
+	if val, err = par.ParseString("test", src); err != nil {
+		return nil, err
+	}
+
+	return *val, nil
+} // func read(src string) (parser.LispValue, error)
+
+// evalString parses a single expression from src and evaluates it.
+func evalString(src string) (parser.LispValue, error) {
+	var (
+		err  error
+		expr parser.LispValue
+	)
+
+	if expr, err = read(src); err != nil {
+		return nil, err
+	}
+
+	return in.Eval(expr)
+} // func evalString(src string) (parser.LispValue, error)
+
+// evalCase is an expression to evaluate along with the printed
+// representation of the expected result.
+type evalCase struct {
+	expr        string
+	result      string
+	expectError bool
+}
+
+// runEvalCases evaluates the given cases in order and compares the printed
+// representation of the results to the expected ones.
+func runEvalCases(t *testing.T, cases []evalCase) {
+	t.Helper()
+
+	for _, c := range cases {
+		var (
+			err error
+			res parser.LispValue
+		)
+
+		if res, err = evalString(c.expr); err != nil {
+			if !c.expectError {
+				t.Errorf("Error evaluating %s: %s",
+					c.expr,
+					err.Error())
+			}
+		} else if c.expectError {
+			t.Errorf("Evaluating %s should have failed, but returned %s",
+				c.expr,
+				res)
+		} else if s := res.String(); s != c.result {
+			t.Errorf("Unexpected result from %s: %s (expected %s)",
+				c.expr,
+				s,
+				c.result)
+		}
+	}
+} // func runEvalCases(t *testing.T, cases []evalCase)
+
 func TestEvalSimple(t *testing.T) {
 	type testCase struct {
 		input          parser.LispValue
@@ -137,7 +207,7 @@ func TestEvalList(t *testing.T) {
 
 	var cases = []testCase{
 		{
-			input:  parser.List{},
+			input:  list(),
 			result: sym("nil"),
 		},
 		{
@@ -200,23 +270,23 @@ func TestEvalFuncall(t *testing.T) {
 	installFunctions()
 
 	type testCase struct {
-		input  parser.List
+		input  *parser.ConsCell
 		output parser.LispValue
 		err    bool
 	}
 
 	var testCases = []testCase{
 		{
-			input:  list(sym("squared"), parser.Integer{Int: 5}).(parser.List),
+			input:  list(sym("squared"), parser.Integer{Int: 5}).(*parser.ConsCell),
 			output: parser.Integer{Int: 25},
 		},
 		{
-			input:  list(sym("squared"), parser.Integer{Int: -9}).(parser.List),
+			input:  list(sym("squared"), parser.Integer{Int: -9}).(*parser.ConsCell),
 			output: parser.Integer{Int: 81},
 		},
 		{
-			input:  list(sym("min"), parser.Integer{Int: 10}, parser.Integer{Int: 2}).(parser.List),
-			output: parser.Integer{Int: 10},
+			input:  list(sym("min"), parser.Integer{Int: 10}, parser.Integer{Int: 2}).(*parser.ConsCell),
+			output: parser.Integer{Int: 2},
 		},
 		{
 			input: list(
@@ -227,7 +297,12 @@ func TestEvalFuncall(t *testing.T) {
 					parser.Integer{Int: 3},
 					parser.Integer{Int: 5},
 					parser.Integer{Int: -8},
-					parser.Integer{Int: 16})).(parser.List),
+					parser.Integer{Int: 16})).(*parser.ConsCell),
+			output: list(
+				parser.Integer{Int: 9},
+				parser.Integer{Int: 25},
+				parser.Integer{Int: 64},
+				parser.Integer{Int: 256}),
 		},
 	}
 
@@ -286,7 +361,7 @@ func installFunctions() {
 					list(
 						sym("cons"),
 						list(
-							sym("apply"),
+							sym("funcall"),
 							sym("fn"),
 							list(sym("car"), sym("lst"))),
 						list(
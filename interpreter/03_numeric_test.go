@@ -0,0 +1,151 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/03_numeric_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 17. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-17 19:20:44 krylon>
+
+package interpreter
+
+import (
+	"math"
+	"testing"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+func TestArithmetic(t *testing.T) {
+	type testCase struct {
+		expr        string
+		result      parser.LispValue
+		expectError bool
+	}
+
+	var cases = []testCase{
+		{expr: "(+ 1 2 3)", result: parser.Integer{Int: 6}},
+		{expr: "(+ 1 2.5)", result: parser.Float{Flt: 3.5}},
+		{expr: "(+ 0.5 0.25)", result: parser.Float{Flt: 0.75}},
+		{expr: "(+ 1.5 -1.5)", result: parser.Float{Flt: 0}},
+		{expr: "(* 2 3)", result: parser.Integer{Int: 6}},
+		{expr: "(* 2 1.5)", result: parser.Float{Flt: 3}},
+		{expr: "(* 1e3 2)", result: parser.Float{Flt: 2000}},
+		{expr: "(*)", result: parser.Integer{Int: 1}},
+		{expr: `(+ 1 "zwei")`, expectError: true},
+		{expr: "(< 1 2.5)", result: sym("t")},
+		{expr: "(< 2.5 1)", result: sym("nil")},
+		{expr: "(< 1 2 3)", result: sym("t")},
+		{expr: "(< 1 3 2)", result: sym("nil")},
+		{expr: "(< 1.0 1)", result: sym("nil")},
+		{expr: "(< 1)", result: sym("t")},
+		{expr: "(<)", expectError: true},
+		{expr: `(< 1 "a")`, expectError: true},
+		{expr: "(- 10 1 2)", result: parser.Integer{Int: 7}},
+		{expr: "(- 5)", result: parser.Integer{Int: -5}},
+		{expr: "(- 1.5)", result: parser.Float{Flt: -1.5}},
+		{expr: "(- 1 0.5)", result: parser.Float{Flt: 0.5}},
+		{expr: "(-)", expectError: true},
+		{expr: "(- 'a)", expectError: true},
+		{expr: "(/ 12 2 3)", result: parser.Integer{Int: 2}},
+		{expr: "(/ 7 2)", result: parser.Float{Flt: 3.5}},
+		{expr: "(/ 4)", result: parser.Float{Flt: 0.25}},
+		{expr: "(/ 1)", result: parser.Integer{Int: 1}},
+		{expr: "(/ 1.0 4)", result: parser.Float{Flt: 0.25}},
+		{expr: "(/)", expectError: true},
+		{expr: "(/ 1 0)", expectError: true},
+		{expr: "(/ 1.5 0.0)", expectError: true},
+		{expr: "(/ 0)", expectError: true},
+		{expr: "(% 7 3)", result: parser.Integer{Int: 1}},
+		{expr: "(% -7 3)", result: parser.Integer{Int: -1}},
+		{expr: "(% 7.5 2)", result: parser.Float{Flt: 1.5}},
+		{expr: "(% 7 0)", expectError: true},
+		{expr: "(% 7)", expectError: true},
+		{expr: "(% 7 2 1)", expectError: true},
+		{expr: "(+ 9223372036854775807 1)", expectError: true},
+		{expr: "(- -9223372036854775807 2)", expectError: true},
+		{expr: "(- 0 -9223372036854775807)", result: parser.Integer{Int: 9223372036854775807}},
+		{expr: "(* 4611686018427387904 2)", expectError: true},
+		{expr: "(* 4611686018427387904 -2)", result: parser.Integer{Int: -9223372036854775808}},
+		{expr: "(* -1 (* 4611686018427387904 -2))", expectError: true},
+		{expr: "(/ (* 4611686018427387904 -2) -1)", expectError: true},
+		{expr: "(- (* 4611686018427387904 -2))", expectError: true},
+		{expr: "(* 1e308 10)", expectError: true},
+		{expr: "(- -1e308 1e308)", expectError: true},
+		{expr: "(+ 1e308 1e308)", expectError: true},
+		{expr: "(/ 1 1e-320)", expectError: true},
+		{expr: "(* 1e200 1e-200)", result: parser.Float{Flt: 1}},
+		{expr: "(> 3 2 1)", result: sym("t")},
+		{expr: "(> 3 3 1)", result: sym("nil")},
+		{expr: "(>= 3 3 1)", result: sym("t")},
+		{expr: "(<= 1 1 2.5)", result: sym("t")},
+		{expr: "(<= 2 1)", result: sym("nil")},
+		{expr: "(= 1 1.0 1)", result: sym("t")},
+		{expr: "(= 1 2)", result: sym("nil")},
+		{expr: "(= 1)", result: sym("t")},
+		{expr: "(=)", expectError: true},
+		{expr: "(> 1 'a)", expectError: true},
+		{expr: "(= 1 2 'a)", expectError: true},
+	}
+
+	for _, c := range cases {
+		var (
+			err       error
+			expr, res parser.LispValue
+		)
+
+		if expr, err = read(c.expr); err != nil {
+			t.Errorf("Cannot parse %q: %s", c.expr, err.Error())
+			continue
+		} else if res, err = in.Eval(expr); err != nil {
+			if !c.expectError {
+				t.Errorf("Error evaluating %s: %s",
+					c.expr,
+					err.Error())
+			}
+		} else if c.expectError {
+			t.Errorf("Evaluating %s should have failed, but returned %s",
+				c.expr,
+				res)
+		} else if !res.Equal(c.result) {
+			t.Errorf("Unexpected result from %s: %s %s (expected %s %s)",
+				c.expr,
+				res.Type(),
+				res,
+				c.result.Type(),
+				c.result)
+		}
+	}
+} // func TestArithmetic(t *testing.T)
+
+func TestArithmeticConditions(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(handler-case (/ 1 0) (division-by-zero () 'div0))", result: "DIV0"},
+		{expr: "(handler-case (% 1.5 0) (arithmetic-error () 'arith))", result: "ARITH"},
+		{expr: "(handler-case (+ 9223372036854775807 1) (division-by-zero () 'div0) (arithmetic-error () 'overflow))", result: "OVERFLOW"},
+		{expr: "(handler-case (- 'a) (type-error () 'type))", result: "TYPE"},
+		{expr: "(handler-case (* 1e308 10) (arithmetic-error () 'overflow))", result: "OVERFLOW"},
+	}
+
+	runEvalCases(t, cases)
+} // func TestArithmeticConditions(t *testing.T)
+
+func TestCompareNaN(t *testing.T) {
+	var (
+		nan  = parser.Float{Flt: math.NaN()}
+		one  = parser.Integer{Int: 1}
+		args = [][]parser.LispValue{
+			{nan, nan},
+			{nan, one},
+			{one, nan},
+			{one, one, nan},
+		}
+	)
+
+	for form := range comparisons {
+		for _, a := range args {
+			if res, err := numCompareAll(form, a); err != nil {
+				t.Errorf("Error comparing %v with %s: %s", a, form, err.Error())
+			} else if asBool(res) {
+				t.Errorf("(%s %v) should be NIL, got %s", form, a, res)
+			}
+		}
+	}
+} // func TestCompareNaN(t *testing.T)
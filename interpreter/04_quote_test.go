@@ -0,0 +1,46 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/04_quote_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 17. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-17 21:14:52 krylon>
+
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+func TestQuote(t *testing.T) {
+	in.Env.Set(sym("qx"), parser.Integer{Int: 1})
+	in.Env.Set(sym("qlst"), list(parser.Integer{Int: 2}, parser.Integer{Int: 3}))
+
+	var cases = []evalCase{
+		{expr: "'a", result: "A"},
+		{expr: "'(1 2 3)", result: "(1 2 3)"},
+		{expr: "(quote (+ 1 2))", result: "(+ 1 2)"},
+		{expr: "''a", result: "(QUOTE A)"},
+		{expr: "(quote)", expectError: true},
+		{expr: "`a", result: "A"},
+		{expr: "`(a qx)", result: "(A QX)"},
+		{expr: "`(a ,qx)", result: "(A 1)"},
+		{expr: "`(a ,(+ qx 1) c)", result: "(A 2 C)"},
+		{expr: "`(a ,@qlst b)", result: "(A 2 3 B)"},
+		{expr: "`(,@qlst)", result: "(2 3)"},
+		{expr: "`(a (b ,qx))", result: "(A (B 1))"},
+		{expr: "`(a `(b ,(c ,qx)))", result: "(A (QUASIQUOTE (B (UNQUOTE (C 1)))))"},
+		{expr: "`(a `(b ,@,@qlst))", result: "(A (QUASIQUOTE (B (UNQUOTE-SPLICING 2 3))))"},
+		{expr: "`(a . ,qx)", result: "(A . 1)"},
+		{expr: "`(a b . ,qlst)", result: "(A B 2 3)"},
+		{expr: "`(,qx . c)", result: "(1 . C)"},
+		{expr: "`(,@qlst . c)", result: "(2 3 . C)"},
+		{expr: "`(a . (b ,qx))", result: "(A B 1)"},
+		{expr: "`(a `(b . ,,qx))", result: "(A (QUASIQUOTE (B UNQUOTE 1)))"},
+		{expr: "`,@qlst", expectError: true},
+		{expr: "`(a ,@qx)", expectError: true},
+		{expr: ",qx", expectError: true},
+	}
+
+	runEvalCases(t, cases)
+} // func TestQuote(t *testing.T)
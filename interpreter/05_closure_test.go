@@ -0,0 +1,38 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/05_closure_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 17. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-17 21:58:20 krylon>
+
+package interpreter
+
+import "testing"
+
+func TestClosure(t *testing.T) {
+	// The cases are evaluated in order, later cases use functions
+	// defined by earlier ones.
+	var cases = []evalCase{
+		{expr: "((lambda (x) (* x x)) 4)", result: "16"},
+		{expr: "((lambda () 42))", result: "42"},
+		{expr: "((lambda (x)))", expectError: true},
+		{expr: "((lambda (x y) (+ x y)) 1)", expectError: true},
+		{expr: "(lambda (1) 1)", expectError: true},
+		{expr: "(lambda)", expectError: true},
+		{expr: "((lambda (sq) (sq 5)) (lambda (x) (* x x)))", result: "25"},
+		{expr: "(defun make-adder (n) (lambda (x) (+ x n)))", result: "MAKE-ADDER"},
+		{expr: "((make-adder 3) 4)", result: "7"},
+		{expr: "(defun twice (f x) (f (f x)))", result: "TWICE"},
+		{expr: "(twice (make-adder 5) 1)", result: "11"},
+		{expr: "(twice (lambda (x) (* x 2)) 3)", result: "12"},
+		{expr: "(defun get-n () n)", result: "GET-N"},
+		{expr: "(defun call-with-n (n) (get-n))", result: "CALL-WITH-N"},
+		{expr: "(call-with-n 23)", expectError: true},
+		{expr: "(defun def-getter (v) (defun getter () v))", result: "DEF-GETTER"},
+		{expr: "(def-getter 42)", result: "GETTER"},
+		{expr: "(getter)", result: "42"},
+		{expr: `(defun documented (x) "Return x." x)`, result: "DOCUMENTED"},
+		{expr: "(documented 9)", result: "9"},
+	}
+
+	runEvalCases(t, cases)
+} // func TestClosure(t *testing.T)
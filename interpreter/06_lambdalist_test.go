@@ -0,0 +1,106 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/06_lambdalist_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 17. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-17 22:48:13 krylon>
+
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+func TestParseLambdaList(t *testing.T) {
+	type testCase struct {
+		src         string
+		min, max    int
+		expectError bool
+	}
+
+	var cases = []testCase{
+		{src: "()", min: 0, max: 0},
+		{src: "(a b)", min: 2, max: 2},
+		{src: "(a &optional b (c 1) (d 2 d-p))", min: 1, max: 4},
+		{src: "(a &rest more)", min: 1, max: -1},
+		{src: "(&key x (y 0))", min: 0, max: -1},
+		{src: "(a &optional b &rest r &key k &allow-other-keys)", min: 1, max: -1},
+		{src: "(a 1)", expectError: true},
+		{src: "(a a)", expectError: true},
+		{src: "(&rest)", expectError: true},
+		{src: "(&rest a b)", expectError: true},
+		{src: "(&key a &optional b)", expectError: true},
+		{src: "(&optional &optional)", expectError: true},
+		{src: "(&optional (a 1 2 3))", expectError: true},
+		{src: "(&optional (a 1 :p))", expectError: true},
+		{src: "(&allow-other-keys)", expectError: true},
+		{src: "(&whatever a)", expectError: true},
+		{src: "(nil)", expectError: true},
+	}
+
+	for _, c := range cases {
+		var (
+			err  error
+			ok   bool
+			v    parser.LispValue
+			args []parser.LispValue
+			ll   *lambdaList
+		)
+
+		if v, err = read(c.src); err != nil {
+			t.Errorf("Cannot parse %s: %s", c.src, err.Error())
+			continue
+		} else if args, ok = listItems(v); !ok {
+			t.Errorf("%s is not a list", c.src)
+			continue
+		} else if ll, err = parseLambdaList(args); err != nil {
+			if !c.expectError {
+				t.Errorf("Error parsing lambda list %s: %s",
+					c.src,
+					err.Error())
+			}
+		} else if c.expectError {
+			t.Errorf("Parsing lambda list %s should have failed", c.src)
+		} else if ll.minArgs() != c.min || ll.maxArgs() != c.max {
+			t.Errorf("Unexpected arity for %s: %d..%d (expected %d..%d)",
+				c.src,
+				ll.minArgs(),
+				ll.maxArgs(),
+				c.min,
+				c.max)
+		}
+	}
+} // func TestParseLambdaList(t *testing.T)
+
+func TestLambdaListCall(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(defun opt (a &optional b (c 10) (d (+ a c) d-p)) `(,a ,b ,c ,d ,d-p))", result: "OPT"},
+		{expr: "(opt 1)", result: "(1 NIL 10 11 NIL)"},
+		{expr: "(opt 1 2)", result: "(1 2 10 11 NIL)"},
+		{expr: "(opt 1 2 3)", result: "(1 2 3 4 NIL)"},
+		{expr: "(opt 1 2 3 4)", result: "(1 2 3 4 T)"},
+		{expr: "(opt)", expectError: true},
+		{expr: "(opt 1 2 3 4 5)", expectError: true},
+		{expr: "(defun rst (a &rest more) `(,a ,more))", result: "RST"},
+		{expr: "(rst 1)", result: "(1 NIL)"},
+		{expr: "(rst 1 2 3)", result: "(1 (2 3))"},
+		{expr: "(defun kw (&key x (y 5) (z 0 z-p)) `(,x ,y ,z ,z-p))", result: "KW"},
+		{expr: "(kw)", result: "(NIL 5 0 NIL)"},
+		{expr: "(kw :y 1)", result: "(NIL 1 0 NIL)"},
+		{expr: "(kw :z 3 :x 2)", result: "(2 5 3 T)"},
+		{expr: "(kw :x 1 :x 2)", result: "(1 5 0 NIL)"},
+		{expr: "(kw :x)", expectError: true},
+		{expr: "(kw :w 1)", expectError: true},
+		{expr: "(kw 1 2)", expectError: true},
+		{expr: "(defun kw-other (&key a &allow-other-keys) a)", result: "KW-OTHER"},
+		{expr: "(kw-other :b 2 :a 1)", result: "1"},
+		{expr: "(defun rest-key (&rest all &key a) `(,a ,all))", result: "REST-KEY"},
+		{expr: "(rest-key :a 1)", result: "(1 (:A 1))"},
+		{expr: "((lambda (&optional (x 7)) x))", result: "7"},
+		{expr: "((lambda (&rest xs) xs) 1 2)", result: "(1 2)"},
+		{expr: "(defun bad (a &rest) a)", expectError: true},
+	}
+
+	runEvalCases(t, cases)
+} // func TestLambdaListCall(t *testing.T)
@@ -0,0 +1,67 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/07_macro_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 17. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-17 23:41:02 krylon>
+
+package interpreter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blicero/krylisp/parser"
+	"github.com/blicero/krylisp/types"
+)
+
+func TestMacro(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(defmacro my-unless (c &body body) `(if ,c nil ((lambda () ,@body))))", result: "MY-UNLESS"},
+		{expr: "(my-unless nil 1 2)", result: "2"},
+		{expr: "(my-unless t 1)", result: "NIL"},
+		{expr: "(macroexpand-1 '(my-unless x y))", result: "(IF X NIL ((LAMBDA NIL Y)))"},
+		{expr: "(defmacro my-unless2 (c x) `(my-unless ,c ,x))", result: "MY-UNLESS2"},
+		{expr: "(macroexpand-1 '(my-unless2 a b))", result: "(MY-UNLESS A B)"},
+		{expr: "(macroexpand '(my-unless2 a b))", result: "(IF A NIL ((LAMBDA NIL B)))"},
+		{expr: "(macroexpand '(+ 1 2))", result: "(+ 1 2)"},
+		{expr: "(macroexpand-1 5)", result: "5"},
+		{expr: "(defmacro quote-it (x) `(quote ,x))", result: "QUOTE-IT"},
+		{expr: "(quote-it (undefined-function 1))", result: "(UNDEFINED-FUNCTION 1)"},
+		{expr: "(defmacro double-it (x) ((lambda (g) `((lambda (,g) (+ ,g ,g)) ,x)) (gensym)))", result: "DOUBLE-IT"},
+		{expr: "(double-it (+ 1 2))", result: "6"},
+		{expr: "(defmacro needs-two (a b) a)", result: "NEEDS-TWO"},
+		{expr: "(needs-two 1)", expectError: true},
+		{expr: "(defmacro)", expectError: true},
+		{expr: "(defmacro 1 (x) x)", expectError: true},
+		{expr: "(macroexpand-1)", expectError: true},
+		{expr: "(gensym 1)", expectError: true},
+	}
+
+	runEvalCases(t, cases)
+} // func TestMacro(t *testing.T)
+
+func TestGensym(t *testing.T) {
+	var (
+		err    error
+		s1, s2 parser.LispValue
+		prefix parser.LispValue
+	)
+
+	if s1, err = evalString("(gensym)"); err != nil {
+		t.Fatalf("Error calling GENSYM: %s", err.Error())
+	} else if s2, err = evalString("(gensym)"); err != nil {
+		t.Fatalf("Error calling GENSYM: %s", err.Error())
+	} else if prefix, err = evalString(`(gensym "TMP")`); err != nil {
+		t.Fatalf("Error calling GENSYM: %s", err.Error())
+	}
+
+	if s1.Type() != types.Symbol {
+		t.Errorf("GENSYM returned a %s, not a Symbol", s1.Type())
+	} else if s1.Equal(s2) {
+		t.Errorf("GENSYM returned the same Symbol twice: %s", s1)
+	} else if !strings.HasPrefix(prefix.String(), "#:TMP") {
+		t.Errorf("GENSYM ignored the prefix: %s", prefix)
+	} else if s1.(parser.Symbol).Atom() == parser.Intern(s1.String()) {
+		t.Errorf("GENSYM returned the interned Symbol %s", s1)
+	}
+} // func TestGensym(t *testing.T)
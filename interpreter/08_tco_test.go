@@ -0,0 +1,101 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/08_tco_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 17. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-17 23:58:14 krylon>
+
+package interpreter
+
+import (
+	"io"
+	"log"
+	"testing"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// quietInterpreter returns an Interpreter that does not log, for tests that
+// evaluate a lot of forms.
+func quietInterpreter() *Interpreter {
+	var qi = &Interpreter{
+		Env: MakeEnvironment(),
+		log: log.New(io.Discard, "", 0),
+	}
+
+	qi.defineFunctions()
+
+	return qi
+} // func quietInterpreter() *Interpreter
+
+func TestTailCall(t *testing.T) {
+	const iterations = "100000"
+
+	var (
+		err  error
+		form parser.LispValue
+		res  parser.LispValue
+		qi   = quietInterpreter()
+		defs = []string{
+			"(defun count-down (n) (if (< n 1) 'done (count-down (+ n -1))))",
+			"(defun count-up (i n acc) (if (< i n) (count-up (+ i 1) n (+ acc 1)) acc))",
+			"(defun ping (n) (if (< n 1) 'ping (pong (+ n -1))))",
+			"(defun pong (n) (if (< n 1) 'pong (ping (+ n -1))))",
+		}
+		cases = []struct {
+			expr   string
+			result string
+		}{
+			{"(count-down " + iterations + ")", "DONE"},
+			{"(count-up 0 " + iterations + " 0)", iterations},
+			{"(ping " + iterations + ")", "PING"},
+			{"((lambda (f) (f " + iterations + ")) count-down)", "DONE"},
+			{"(labels ((down (n) (let ((m (+ n -1))) (if (< m 0) 'done (down m))))) (down " + iterations + "))", "DONE"},
+		}
+	)
+
+	for _, src := range defs {
+		if form, err = read(src); err != nil {
+			t.Fatalf("Cannot parse %q: %s", src, err.Error())
+		} else if _, err = qi.Eval(form); err != nil {
+			t.Fatalf("Cannot evaluate %q: %s", src, err.Error())
+		}
+	}
+
+	var root = qi.Env.scope
+
+	for _, c := range cases {
+		if form, err = read(c.expr); err != nil {
+			t.Fatalf("Cannot parse %q: %s", c.expr, err.Error())
+		} else if res, err = qi.Eval(form); err != nil {
+			t.Errorf("Error evaluating %q: %s", c.expr, err.Error())
+		} else if res.String() != c.result {
+			t.Errorf("Unexpected result for %q: expected %s, got %s",
+				c.expr,
+				c.result,
+				res)
+		} else if qi.Env.scope != root {
+			t.Errorf("Scope was not restored after evaluating %q", c.expr)
+		}
+	}
+} // func TestTailCall(t *testing.T)
+
+func TestTailCallError(t *testing.T) {
+	var (
+		err  error
+		form parser.LispValue
+		qi   = quietInterpreter()
+		root = qi.Env.scope
+	)
+
+	if form, err = read("(defun fail-at (n) (if (< n 1) (undefined-function n) (fail-at (+ n -1))))"); err != nil {
+		t.Fatalf("Cannot parse DEFUN: %s", err.Error())
+	} else if _, err = qi.Eval(form); err != nil {
+		t.Fatalf("Cannot evaluate DEFUN: %s", err.Error())
+	} else if form, err = read("(fail-at 10)"); err != nil {
+		t.Fatalf("Cannot parse call: %s", err.Error())
+	} else if _, err = qi.Eval(form); err == nil {
+		t.Errorf("Calling FAIL-AT should have returned an error")
+	} else if qi.Env.scope != root {
+		t.Errorf("Scope was not restored after an error in a tail call")
+	}
+} // func TestTailCallError(t *testing.T)
@@ -0,0 +1,85 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/09_condition_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 01:12:40 krylon>
+
+package interpreter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCondition(t *testing.T) {
+	var cases = []evalCase{
+		{expr: `(handler-case (error "boom") (error (c) (condition-message c)))`, result: `"boom"`},
+		{expr: `(handler-case (error "~A is ~S~~" "x" 'y) (error (c) (condition-message c)))`, result: `"x is Y~"`},
+		{expr: `(handler-case (error "boom") (error (c) (condition-type c)))`, result: "SIMPLE-ERROR"},
+		{expr: `(handler-case (error 'type-error "bad ~A" 42) (arithmetic-error () 1) (type-error (c) (condition-message c)))`, result: `"bad 42"`},
+		{expr: `(handler-case (error 'division-by-zero) (arithmetic-error (c) (condition-type c)))`, result: "DIVISION-BY-ZERO"},
+		{expr: `(handler-case (error 'division-by-zero) (t () 'caught))`, result: "CAUGHT"},
+		{expr: `(handler-case (+ 1 2) (error () 'caught))`, result: "3"},
+		{expr: `(handler-case (error 'type-error) (arithmetic-error () 1))`, expectError: true},
+		{expr: `(handler-case (+ 1 "a") (type-error (c) (condition-type c)))`, result: "TYPE-ERROR"},
+		{expr: `(handler-case no-such-variable (unbound-variable () 'unbound))`, result: "UNBOUND"},
+		{expr: `(handler-case (no-such-function 1) (undefined-function () 'undefined))`, result: "UNDEFINED"},
+		{expr: `(handler-case ((lambda (x) (error "in ~A" x)) 'lambda) (error (c) (condition-message c)))`, result: `"in LAMBDA"`},
+		{expr: `(define-condition my-error (error))`, result: "MY-ERROR"},
+		{expr: `(define-condition my-sub-error (my-error))`, result: "MY-SUB-ERROR"},
+		{expr: `(handler-case (error 'my-sub-error) (my-error (c) (condition-type c)))`, result: "MY-SUB-ERROR"},
+		{expr: `(handler-case (error 'my-error) (serious-condition () 'serious))`, result: "SERIOUS"},
+		{expr: `(signal "nobody listens")`, result: "NIL"},
+		{expr: `(handler-case (signal 'my-error) (my-error () 'signalled))`, result: "SIGNALLED"},
+		{expr: `(handler-case (signal "not an error") (error () 'error) (condition () 'condition))`, result: "CONDITION"},
+		{expr: `(ignore-errors (error "boom") 1)`, result: "NIL"},
+		{expr: `(ignore-errors 1 2)`, result: "2"},
+		{expr: `(ignore-errors (signal 'warning) 1)`, result: "1"},
+		{expr: `(handler-case (ignore-errors (error "inner")) (error () 'outer))`, result: "NIL"},
+		{expr: `(unwind-protect 1 2)`, result: "1"},
+		{expr: `(handler-case (unwind-protect (error "boom") (defun cleaned-up () t)) (error () (cleaned-up)))`, result: "T"},
+		{expr: `(unwind-protect 1 (error "cleanup failed"))`, expectError: true},
+		{expr: `(error "unhandled")`, expectError: true},
+		{expr: `(error 'no-such-condition)`, expectError: true},
+		{expr: `(error 42)`, expectError: true},
+		{expr: `(error "~A")`, expectError: true},
+		{expr: `(handler-case 1 (no-such-condition () 2))`, expectError: true},
+		{expr: `(define-condition error (condition))`, expectError: true},
+		{expr: `(define-condition my-error (no-such-condition))`, expectError: true},
+		{expr: `(define-condition my-error (my-sub-error))`, expectError: true},
+		{expr: `(condition-message 1)`, expectError: true},
+	}
+
+	runEvalCases(t, cases)
+} // func TestCondition(t *testing.T)
+
+func TestConditionError(t *testing.T) {
+	var (
+		err error
+		c   *Condition
+	)
+
+	if _, err = evalString(`(error 'type-error "bad ~A ~S" "x" "y")`); err == nil {
+		t.Fatal("ERROR did not return an error")
+	} else if !errors.As(err, &c) {
+		t.Fatalf("ERROR returned a %T, not a Condition", err)
+	} else if c.CondType() != "TYPE-ERROR" {
+		t.Errorf("Unexpected condition type %s (expected TYPE-ERROR)", c.CondType())
+	} else if c.Message() != `bad x "y"` {
+		t.Errorf("Unexpected message %q (expected %q)", c.Message(), `bad x "y"`)
+	}
+
+	if c = AsCondition(ErrType); c.CondType() != "TYPE-ERROR" {
+		t.Errorf("ErrType was converted to %s, not TYPE-ERROR", c.CondType())
+	} else if !errors.Is(c, ErrType) {
+		t.Error("Condition made from ErrType does not wrap it")
+	} else if c = AsCondition(ErrEval); c.CondType() != "EVAL-ERROR" {
+		t.Errorf("ErrEval was converted to %s, not EVAL-ERROR", c.CondType())
+	} else if c = AsCondition(errors.New("whatever")); c.CondType() != "SIMPLE-ERROR" {
+		t.Errorf("Plain error was converted to %s, not SIMPLE-ERROR", c.CondType())
+	}
+
+	if len(in.handlers) != 0 {
+		t.Errorf("Handler stack was not unwound: %v", in.handlers)
+	}
+} // func TestConditionError(t *testing.T)
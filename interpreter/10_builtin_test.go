@@ -0,0 +1,136 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/10_builtin_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 02:03:51 krylon>
+
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+func TestEnvironmentDefine(t *testing.T) {
+	var (
+		err error
+		ok  bool
+		val parser.LispValue
+		ip  *Interpreter
+		env = MakeEnvironment()
+	)
+
+	env.Define("greeting", parser.String{Str: "Hello"})
+
+	if val, ok = env.Lookup(parser.Symbol{Sym: "GREETING"}); !ok {
+		t.Fatal("Lookup of GREETING failed after Define")
+	} else if !val.Equal(parser.String{Str: "Hello"}) {
+		t.Errorf("Lookup of GREETING returned %s", val)
+	}
+
+	env.Push()
+	env.Define("nested", parser.Integer{Int: 1})
+	env.Pop()
+
+	if _, ok = env.Lookup(parser.Symbol{Sym: "NESTED"}); !ok {
+		t.Error("Define in nested scope did not create a global binding")
+	}
+
+	if ip, err = MakeInterpreter(env, false); err != nil {
+		t.Fatalf("Cannot create Interpreter: %s", err.Error())
+	} else if ip.Env != env {
+		t.Fatal("MakeInterpreter did not use the Environment passed to it")
+	} else if val, err = ip.Eval(parser.Symbol{Sym: "GREETING"}); err != nil {
+		t.Errorf("Error evaluating GREETING: %s", err.Error())
+	} else if !val.Equal(parser.String{Str: "Hello"}) {
+		t.Errorf("GREETING evaluated to %s", val)
+	}
+} // func TestEnvironmentDefine(t *testing.T)
+
+func TestRegisterBuiltin(t *testing.T) {
+	var (
+		err     error
+		form    parser.LispValue
+		res     parser.LispValue
+		qi      = quietInterpreter()
+		errBoom = errors.New("boom")
+	)
+
+	var builtins = map[string]BuiltinFunc{
+		"go-sum": func(args []parser.LispValue) (parser.LispValue, error) {
+			var sum int64
+
+			for _, a := range args {
+				var i, ok = a.(parser.Integer)
+
+				if !ok {
+					return nil, fmt.Errorf("%w: GO-SUM expects integers, not %s",
+						ErrType,
+						a)
+				}
+
+				sum += i.Int
+			}
+
+			return parser.Integer{Int: sum}, nil
+		},
+		"go-fail": func(args []parser.LispValue) (parser.LispValue, error) {
+			return nil, errBoom
+		},
+		"go-void": func(args []parser.LispValue) (parser.LispValue, error) {
+			return nil, nil
+		},
+	}
+
+	for name, fn := range builtins {
+		if err = qi.RegisterBuiltin(name, fn); err != nil {
+			t.Fatalf("Cannot register builtin %s: %s", name, err.Error())
+		}
+	}
+
+	var cases = []evalCase{
+		{expr: "(go-sum 1 2 3)", result: "6"},
+		{expr: "(go-sum (go-sum 1 2) (+ 3 4))", result: "10"},
+		{expr: "(go-sum)", result: "0"},
+		{expr: "(go-void)", result: "NIL"},
+		{expr: "go-sum", result: "#<BUILTIN GO-SUM>"},
+		{expr: "((lambda (f) (f 20 22)) go-sum)", result: "42"},
+		{expr: "(defun twice (f x) (f (f x x) (f x x)))", result: "TWICE"},
+		{expr: "(twice go-sum 3)", result: "12"},
+		{expr: "(handler-case (go-sum 1 \"a\") (type-error () 'type-error))", result: "TYPE-ERROR"},
+		{expr: "(handler-case (go-fail) (error (c) (condition-type c)))", result: "SIMPLE-ERROR"},
+		{expr: "(go-sum 'a)", expectError: true},
+	}
+
+	for _, c := range cases {
+		if form, err = read(c.expr); err != nil {
+			t.Fatalf("Cannot parse %q: %s", c.expr, err.Error())
+		} else if res, err = qi.Eval(form); err != nil {
+			if !c.expectError {
+				t.Errorf("Error evaluating %q: %s", c.expr, err.Error())
+			}
+		} else if c.expectError {
+			t.Errorf("Evaluating %q should have failed, but returned %s", c.expr, res)
+		} else if res.String() != c.result {
+			t.Errorf("Unexpected result for %q: expected %s, got %s",
+				c.expr,
+				c.result,
+				res)
+		}
+	}
+
+	if form, err = read("(go-fail)"); err != nil {
+		t.Fatalf("Cannot parse (go-fail): %s", err.Error())
+	} else if _, err = qi.Eval(form); !errors.Is(err, errBoom) {
+		t.Errorf("Error from builtin was not passed on: %v", err)
+	}
+
+	for _, name := range []string{"if", "nil", ":key", ""} {
+		if err = qi.RegisterBuiltin(name, builtins["go-void"]); err == nil {
+			t.Errorf("Registering builtin %q should have failed", name)
+		}
+	}
+} // func TestRegisterBuiltin(t *testing.T)
@@ -0,0 +1,252 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/11_vm_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 11:20:36 krylon>
+
+package interpreter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// vmDefs are evaluated before the cases of TestVM and the benchmarks.
+var vmDefs = []string{
+	"(defun fib (n) (if (< n 2) n (+ (fib (+ n -1)) (fib (+ n -2)))))",
+	"(defun count-up (i n acc) (if (< i n) (count-up (+ i 1) n (+ acc i)) acc))",
+	"(defun make-adder (n) (lambda (x) (+ x n)))",
+	"(defun compose (f g) (lambda (x) (f (g x))))",
+	"(defun opt (a &optional (b (* a 2) b-p) (c 10) &rest more) (if more more (if b-p (+ a b c) (* a b c))))",
+	"(defun nested (a) (lambda (b) (lambda (c) (+ a b c))))",
+	"(defun shadow (n) ((lambda (n) (* n n)) (+ n 1)))",
+	"(defmacro unless2 (c a b) `(if ,c ,b ,a))",
+	"(defun use-macro (x) (unless2 (< x 0) 'positive 'negative))",
+	"(defun keys (&key (a 1)) a)",
+	"(defun late-caller (x) (late-macro x))",
+	"(defun late-nested (a) (lambda (b) (late-macro (+ a b))))",
+	"(defmacro late-macro (x) `(+ ,x 1))",
+	"(defun late-binder (*late*) (late-reader))",
+	"(defun late-reader () *late*)",
+	"(defvar *late* 1)",
+}
+
+func TestVM(t *testing.T) {
+	type testCase struct {
+		expr     string
+		compiled bool
+	}
+
+	var (
+		err   error
+		form  parser.LispValue
+		tree  = quietInterpreter()
+		vm    = quietInterpreter()
+		cases = []testCase{
+			{"42", true},
+			{"'sym", true},
+			{"()", true},
+			{"(+ 1 2.5 (* 3 4))", true},
+			{"(if (< 1 2) 'yes 'no)", true},
+			{"(if nil 'yes)", false},
+			{"(fib 15)", true},
+			{"(count-up 0 10000 0)", true},
+			{"((make-adder 3) 4)", true},
+			{"((compose (make-adder 1) (make-adder 10)) 100)", true},
+			{"(((nested 1) 2) 3)", true},
+			{"(shadow 4)", true},
+			{"(use-macro 3)", true},
+			{"(use-macro -3)", true},
+			{"(opt 1)", true},
+			{"(opt 1 2)", true},
+			{"(opt 1 2 3 4 5)", true},
+			{"(keys :a 5)", true},
+			{"(defun square (x) (* x x))", true},
+			{"(square 9)", true},
+			{"make-adder", true},
+			{"(lambda (x &optional y) (+ x y))", true},
+			{"((lambda (&rest xs) xs))", true},
+			{"unbound-variable", true},
+			{"(undefined-function 1)", true},
+			{"(+ 1 'a)", true},
+			{"(fib)", true},
+			{"((make-adder 1) 1 2)", true},
+			{"(42 1)", false},
+			{"((quote fib) 1)", true},
+			{"(list 1 2)", false},
+			{"(not (< 2 1))", true},
+			{"(+ (- 10 3 2) (/ 7 2) (% 7 3))", true},
+			{"(if (>= 3 3 1) (= 1 1.0) (<= 2 1))", true},
+			{"((lambda (x) (- (/ x 2) (% x 3))) 10)", true},
+			{"(/ 1 0)", true},
+			{"(* 9223372036854775807 2)", true},
+			{"(cond ((< 2 1) 'a) (t 'b))", false},
+			{"(cadr (nth 1 '((a) (b c))))", true},
+			{"(length (member 2 '(1 2 3)))", true},
+			{"(reverse '(1 2 3))", false},
+			{"(if (eq 'a 'a) (eql 1 1.0) (equal '(1) '(1)))", true},
+			{"(+ (gethash 'a #h((a . 1))) (gethash 'b #h((a . 1)) 10))", false},
+			{"(hash-table-contains-p 'b #h((a . 1)))", true},
+			{"((lambda (f) (f 1 2)) +)", true},
+			{"(late-caller 0)", true},
+			{"(funcall 'late-caller 0)", false},
+			{"((late-nested 1) 2)", true},
+			{"(late-binder 5)", true},
+			{"(list (late-binder 5) *late*)", false},
+		}
+	)
+
+	for _, src := range vmDefs {
+		if form, err = read(src); err != nil {
+			t.Fatalf("Cannot parse %q: %s", src, err.Error())
+		} else if _, err = tree.Eval(form); err != nil {
+			t.Fatalf("Cannot evaluate %q: %s", src, err.Error())
+		} else if _, err = vm.EvalCompiled(form); err != nil {
+			t.Fatalf("Cannot evaluate %q with the VM: %s", src, err.Error())
+		}
+	}
+
+	for _, c := range cases {
+		var (
+			res1, res2 parser.LispValue
+			err1, err2 error
+		)
+
+		if form, err = read(c.expr); err != nil {
+			t.Fatalf("Cannot parse %q: %s", c.expr, err.Error())
+		} else if _, err = vm.Compile(form); (err == nil) != c.compiled {
+			t.Errorf("Compiling %q: expected compiled = %t, got error %v",
+				c.expr,
+				c.compiled,
+				err)
+		} else if err != nil && !errors.Is(err, ErrNotCompilable) {
+			t.Errorf("Compiling %q failed with unexpected error: %s",
+				c.expr,
+				err.Error())
+		}
+
+		res1, err1 = tree.Eval(form)
+		res2, err2 = vm.EvalCompiled(form)
+
+		if (err1 == nil) != (err2 == nil) {
+			t.Errorf("Results differ for %q:\ntree-walker: %v / %v\nVM:          %v / %v",
+				c.expr,
+				res1,
+				err1,
+				res2,
+				err2)
+		} else if err1 != nil {
+			if ct1, ct2 := AsCondition(err1).CondType(), AsCondition(err2).CondType(); ct1 != ct2 {
+				t.Errorf("Errors differ for %q: %s (%s) vs. %s (%s)",
+					c.expr,
+					ct1,
+					err1.Error(),
+					ct2,
+					err2.Error())
+			}
+		} else if res1.String() != res2.String() {
+			t.Errorf("Results differ for %q: %s vs. %s",
+				c.expr,
+				res1,
+				res2)
+		}
+	}
+
+	if vm.Env.scope != vm.Env.root() {
+		t.Error("Scope was not restored after running compiled code")
+	}
+} // func TestVM(t *testing.T)
+
+func TestVMTailCall(t *testing.T) {
+	var (
+		err  error
+		form parser.LispValue
+		res  parser.LispValue
+		vm   = quietInterpreter()
+		defs = []string{
+			"(defun ping (n) (if (< n 1) 'ping (pong (+ n -1))))",
+			"(defun pong (n) (if (< n 1) 'pong (ping (+ n -1))))",
+		}
+	)
+
+	for _, src := range defs {
+		if form, err = read(src); err != nil {
+			t.Fatalf("Cannot parse %q: %s", src, err.Error())
+		} else if _, err = vm.EvalCompiled(form); err != nil {
+			t.Fatalf("Cannot evaluate %q: %s", src, err.Error())
+		}
+	}
+
+	if form, err = read("(ping 100001)"); err != nil {
+		t.Fatalf("Cannot parse call: %s", err.Error())
+	}
+
+	var code *Code
+
+	if code, err = vm.Compile(form); err != nil {
+		t.Fatalf("Cannot compile %s: %s", form, err.Error())
+	} else if res, err = vm.Run(code); err != nil {
+		t.Errorf("Error running %s: %s", form, err.Error())
+	} else if res.String() != "PONG" {
+		t.Errorf("Unexpected result: %s", res)
+	}
+} // func TestVMTailCall(t *testing.T)
+
+// benchmarkEval evaluates expr repeatedly, either with the tree-walker or
+// with the VM. Compiling the form is part of each iteration, as it is in
+// EvalCompiled.
+func benchmarkEval(b *testing.B, expr string, compiled bool) {
+	var (
+		err  error
+		form parser.LispValue
+		qi   = quietInterpreter()
+		eval = qi.Eval
+	)
+
+	if compiled {
+		eval = qi.EvalCompiled
+	}
+
+	for _, src := range vmDefs {
+		if form, err = read(src); err != nil {
+			b.Fatalf("Cannot parse %q: %s", src, err.Error())
+		} else if _, err = eval(form); err != nil {
+			b.Fatalf("Cannot evaluate %q: %s", src, err.Error())
+		}
+	}
+
+	if form, err = read(expr); err != nil {
+		b.Fatalf("Cannot parse %q: %s", expr, err.Error())
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err = eval(form); err != nil {
+			b.Fatalf("Error evaluating %q: %s", expr, err.Error())
+		}
+	}
+} // func benchmarkEval(b *testing.B, expr string, compiled bool)
+
+func BenchmarkEval(b *testing.B) {
+	var cases = []struct {
+		name string
+		expr string
+	}{
+		{"Fib", "(fib 15)"},
+		{"Loop", "(count-up 0 10000 0)"},
+		{"Closure", "((compose (make-adder 1) (make-adder 10)) 100)"},
+		{"Macro", "(use-macro 3)"},
+	}
+
+	for _, c := range cases {
+		b.Run(c.name+"/TreeWalker", func(b *testing.B) {
+			benchmarkEval(b, c.expr, false)
+		})
+		b.Run(c.name+"/VM", func(b *testing.B) {
+			benchmarkEval(b, c.expr, true)
+		})
+	}
+} // func BenchmarkEval(b *testing.B)
@@ -0,0 +1,135 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/12_limits_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 12:58:44 krylon>
+
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+func TestEvalContext(t *testing.T) {
+	var (
+		err  error
+		form parser.LispValue
+		defs = []string{
+			"(defun spin () (spin))",
+			"(defun deep (n) (+ 1 (deep n)))",
+			"(defun grow (acc) (grow (cons 1 acc)))",
+			"(defun rest-args (&rest xs) (rest-args 1 2 3))",
+		}
+		cases = []struct {
+			expr     string
+			limits   Limits
+			timeout  time.Duration
+			compiled bool
+			expected error
+		}{
+			{expr: "(spin)", timeout: 20 * time.Millisecond, expected: context.DeadlineExceeded},
+			{expr: "(spin)", timeout: 20 * time.Millisecond, compiled: true, expected: context.DeadlineExceeded},
+			{expr: "(spin)", limits: Limits{MaxSteps: 1000}, expected: ErrStepLimit},
+			{expr: "(spin)", limits: Limits{MaxSteps: 1000}, compiled: true, expected: ErrStepLimit},
+			{expr: "(ignore-errors (spin))", limits: Limits{MaxSteps: 1000}, expected: ErrStepLimit},
+			{expr: "(handler-case (spin) (t () 'caught))", limits: Limits{MaxSteps: 1000}, expected: ErrStepLimit},
+			{expr: "(deep 1)", limits: Limits{MaxDepth: 100}, expected: ErrDepthLimit},
+			{expr: "(deep 1)", limits: Limits{MaxDepth: 100}, compiled: true, expected: ErrDepthLimit},
+			{expr: "(grow nil)", limits: Limits{MaxConses: 1000}, expected: ErrConsLimit},
+			{expr: "(rest-args)", limits: Limits{MaxConses: 1000}, expected: ErrConsLimit},
+			{expr: "(rest-args)", limits: Limits{MaxConses: 1000}, compiled: true, expected: ErrConsLimit},
+			{expr: "(+ 1 2)", limits: Limits{MaxSteps: 10, MaxDepth: 10, MaxConses: 10}},
+			{expr: "(cons 1 (cons 2 nil))", limits: Limits{MaxConses: 10}},
+		}
+	)
+
+	for _, c := range cases {
+		var (
+			ctx    = context.Background()
+			cancel = context.CancelFunc(func() {})
+			qi     = quietInterpreter()
+			eval   = qi.Eval
+		)
+
+		if c.compiled {
+			eval = qi.EvalCompiled
+		}
+
+		for _, src := range defs {
+			if form, err = read(src); err != nil {
+				t.Fatalf("Cannot parse %q: %s", src, err.Error())
+			} else if _, err = eval(form); err != nil {
+				t.Fatalf("Cannot evaluate %q: %s", src, err.Error())
+			}
+		}
+
+		if c.timeout != 0 {
+			ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		}
+
+		qi.Limits = c.limits
+
+		if form, err = read(c.expr); err != nil {
+			t.Fatalf("Cannot parse %q: %s", c.expr, err.Error())
+		} else if c.compiled {
+			var code *Code
+
+			if code, err = qi.Compile(form); err != nil {
+				t.Fatalf("Cannot compile %q: %s", c.expr, err.Error())
+			}
+
+			form = list(parser.Symbol{Sym: "FUNCALL-CODE"})
+			qi.Env.Define("funcall-code", &Closure{proto: code.main, env: qi.Env.scope})
+		}
+
+		_, err = qi.EvalContext(ctx, form)
+		cancel()
+
+		if c.expected == nil && err != nil {
+			t.Errorf("Error evaluating %q: %s", c.expr, err.Error())
+		} else if !errors.Is(err, c.expected) {
+			t.Errorf("Evaluating %q: expected %v, got %v", c.expr, c.expected, err)
+		} else if qi.budget != nil {
+			t.Errorf("Budget was not reset after evaluating %q", c.expr)
+		}
+	}
+} // func TestEvalContext(t *testing.T)
+
+func TestEvalContextCancelled(t *testing.T) {
+	var (
+		err         error
+		qi          = quietInterpreter()
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+
+	cancel()
+
+	if _, err = qi.EvalContext(ctx, parser.Integer{Int: 1}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	} else if !errors.Is(err, ErrCancelled) {
+		t.Errorf("Error does not wrap ErrCancelled: %v", err)
+	}
+} // func TestEvalContextCancelled(t *testing.T)
+
+func TestEvalCircular(t *testing.T) {
+	var (
+		err  error
+		qi   = quietInterpreter()
+		args = parser.Cons(parser.Integer{Int: 1}, nil)
+		form = parser.Cons(sym("+"), args)
+	)
+
+	// (+ 1 1 1 ...) never ends, so it has to run into the step limit
+	// instead of hanging.
+	args.Cdr = args
+	qi.Limits = Limits{MaxSteps: 1000}
+
+	if _, err = qi.EvalContext(context.Background(), form); !errors.Is(err, ErrStepLimit) {
+		t.Errorf("Expected ErrStepLimit for a circular List, got %v", err)
+	}
+} // func TestEvalCircular(t *testing.T)
@@ -0,0 +1,204 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/13_sandbox_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 14:46:12 krylon>
+
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+const sandboxSecret = "The cake is a lie"
+
+// sandboxDir creates a directory with a secret file to read and a victim
+// file to delete.
+func sandboxDir(t *testing.T) (dir, secret, victim string) {
+	t.Helper()
+
+	dir = t.TempDir()
+	secret = filepath.Join(dir, "secret.txt")
+	victim = filepath.Join(dir, "victim.txt")
+
+	for _, path := range []string{secret, victim} {
+		if err := os.WriteFile(path, []byte(sandboxSecret), 0600); err != nil {
+			t.Fatalf("Cannot create %s: %s", path, err.Error())
+		}
+	}
+
+	return dir, secret, victim
+} // func sandboxDir(t *testing.T) (dir, secret, victim string)
+
+// sandboxEval evaluates all forms in src in order and returns the value of
+// the last one.
+func sandboxEval(in *Interpreter, src string, compiled bool) (parser.LispValue, error) {
+	var (
+		err  error
+		prog *parser.Program
+		res  parser.LispValue
+		eval = in.Eval
+	)
+
+	if compiled {
+		eval = in.EvalCompiled
+	}
+
+	if prog, err = parser.NewProgram().ParseString("sandbox", src); err != nil {
+		return nil, err
+	}
+
+	for _, form := range prog.Forms {
+		if res, err = eval(form); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+} // func sandboxEval(in *Interpreter, src string, compiled bool) (parser.LispValue, error)
+
+func TestSandboxProfiles(t *testing.T) {
+	const envVar = "KRYLISP_SANDBOX_TEST"
+
+	var dir, secret, victim = sandboxDir(t)
+
+	t.Setenv(envVar, sandboxSecret)
+
+	type testCase struct {
+		expr  string
+		needs Capability
+	}
+
+	var (
+		out   = filepath.Join(dir, "out.txt")
+		cases = []testCase{
+			{fmt.Sprintf("(read-file %q)", secret), CapReadFS},
+			{fmt.Sprintf("(file-exists-p %q)", secret), CapReadFS},
+			{fmt.Sprintf("(directory %q)", dir), CapReadFS},
+			{fmt.Sprintf("(write-file %q \"x\")", out), CapWriteFS},
+			{fmt.Sprintf("(delete-file %q)", victim), CapWriteFS},
+			{fmt.Sprintf("(getenv %q)", envVar), CapEnv},
+		}
+	)
+
+	if echo, err := exec.LookPath("echo"); err == nil {
+		cases = append(cases, testCase{fmt.Sprintf("(run-program %q \"hi\")", echo), CapExec})
+	}
+
+	for _, profile := range Profiles() {
+		var (
+			err  error
+			in   *Interpreter
+			caps = profiles[profile]
+		)
+
+		if in, err = MakeSandbox(profile, false); err != nil {
+			t.Fatalf("Cannot create sandbox with profile %s: %s", profile, err.Error())
+		} else if in.Profile() != profile {
+			t.Errorf("Sandbox has profile %q, expected %q", in.Profile(), profile)
+		}
+
+		for _, c := range cases {
+			var (
+				res     parser.LispValue
+				allowed = caps&c.needs == c.needs
+			)
+
+			res, err = sandboxEval(in, c.expr, false)
+
+			if allowed && err != nil {
+				t.Errorf("Profile %s: Error evaluating %s: %s", profile, c.expr, err.Error())
+			} else if !allowed && !errors.Is(err, ErrSecurity) {
+				t.Errorf("Profile %s: Evaluating %s should have failed with a security error, got %v / %v",
+					profile,
+					c.expr,
+					res,
+					err)
+			} else if !allowed && AsCondition(err).CondType() != "SECURITY-ERROR" {
+				t.Errorf("Profile %s: Security error is a %s condition",
+					profile,
+					AsCondition(err).CondType())
+			}
+		}
+
+		if _, err = os.Stat(out); (err == nil) != (caps&CapWriteFS != 0) {
+			t.Errorf("Profile %s: Unexpected state of %s: %v", profile, out, err)
+		}
+
+		_ = os.Remove(out)
+		_ = os.WriteFile(victim, []byte(sandboxSecret), 0600)
+	}
+} // func TestSandboxProfiles(t *testing.T)
+
+// TestSandboxEscape tries various ways to get at the capabilities a profile
+// denies, and checks that none of them leaks the secret or touches the
+// file system.
+func TestSandboxEscape(t *testing.T) {
+	var (
+		dir, secret, victim = sandboxDir(t)
+		out                 = filepath.Join(dir, "out.txt")
+		attempts            = []string{
+			"(read-file SECRET)",
+			"((lambda (f) (f SECRET)) read-file)",
+			"(defun sneaky (p) (read-file p)) (sneaky SECRET)",
+			"(defmacro sneak (p) `(read-file ,p)) (sneak SECRET)",
+			"(handler-case (read-file SECRET) (security-error () (read-file SECRET)))",
+			"(handler-case (read-file SECRET) (t (c) c))",
+			"(ignore-errors (read-file SECRET))",
+			"(unwind-protect (read-file SECRET) (read-file SECRET))",
+			"(apply read-file (quote (SECRET)))",
+			"(defun read-file (p) (run-program \"cat\" p)) (read-file SECRET)",
+			"(run-program \"cat\" SECRET)",
+			"(getenv \"HOME\")",
+			"(write-file OUT SECRET)",
+			"(delete-file VICTIM)",
+			"((lambda (&rest args) (delete-file (car args))) VICTIM)",
+		}
+		replacer = strings.NewReplacer(
+			"SECRET", fmt.Sprintf("%q", secret),
+			"VICTIM", fmt.Sprintf("%q", victim),
+			"OUT", fmt.Sprintf("%q", out),
+		)
+	)
+
+	for _, profile := range []string{"pure", "read-only-fs"} {
+		for _, compiled := range []bool{false, true} {
+			for _, a := range attempts {
+				var (
+					err error
+					in  *Interpreter
+					res parser.LispValue
+					src = replacer.Replace(a)
+				)
+
+				if in, err = MakeSandbox(profile, false); err != nil {
+					t.Fatalf("Cannot create sandbox with profile %s: %s", profile, err.Error())
+				}
+
+				res, err = sandboxEval(in, src, compiled)
+
+				if err == nil && profile == "pure" && strings.Contains(res.String(), sandboxSecret) {
+					t.Errorf("Profile %s: %s leaked the secret: %s", profile, src, res)
+				} else if _, err = os.Stat(victim); err != nil {
+					t.Fatalf("Profile %s: %s deleted the victim: %s", profile, src, err.Error())
+				} else if _, err = os.Stat(out); err == nil {
+					t.Fatalf("Profile %s: %s wrote a file", profile, src)
+				}
+			}
+		}
+	}
+} // func TestSandboxEscape(t *testing.T)
+
+func TestSandboxUnknownProfile(t *testing.T) {
+	if _, err := MakeSandbox("root", false); err == nil {
+		t.Error("MakeSandbox should reject an unknown profile")
+	}
+} // func TestSandboxUnknownProfile(t *testing.T)
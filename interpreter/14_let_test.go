@@ -0,0 +1,63 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/14_let_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 15:52:07 krylon>
+
+package interpreter
+
+import "testing"
+
+func TestLet(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(let ((x 1) (y 2)) (+ x y))", result: "3"},
+		{expr: "(let (x (y) (z 3)) z)", result: "3"},
+		{expr: "(let (x) x)", result: "NIL"},
+		{expr: "(let ())", result: "NIL"},
+		{expr: "(let ((x 1)) (let ((x 2) (y x)) y))", result: "1"},
+		{expr: "(let ((x 1)) (let* ((x 2) (y x)) y))", result: "2"},
+		{expr: "(let* ((x 1) (x (+ x 1))) x)", result: "2"},
+		{expr: "(let ((x 1)) (defun let-getter () x))", result: "LET-GETTER"},
+		{expr: "(let-getter)", result: "1"},
+		{expr: "((let ((n 10)) (lambda (x) (+ x n))) 5)", result: "15"},
+		{expr: "(let ((x 1)) 'ignored (+ x 1))", result: "2"},
+		{expr: "(letrec ((ev (lambda (n) (if (< n 1) t (od (+ n -1))))) (od (lambda (n) (if (< n 1) nil (ev (+ n -1)))))) (ev 10))", result: "T"},
+		{expr: "(labels ((fact (n) (if (< n 2) 1 (* n (fact (+ n -1)))))) (fact 10))", result: "3628800"},
+		{expr: "(labels ((ev (n) (if (< n 1) t (od (+ n -1)))) (od (n) (if (< n 1) nil (ev (+ n -1))))) (od 7))", result: "T"},
+		{expr: "(labels () 1)", result: "1"},
+		{expr: "(labels ((count (n) (if (< n 1) 'done (count (+ n -1))))) (count 1000))", result: "DONE"},
+		{expr: "(let ((let-local 1)) let-local)", result: "1"},
+		{expr: "let-local", expectError: true},
+		{expr: "(let ((x 1) (x 2)) x)", expectError: true},
+		{expr: "(let ((1 2)) 1)", expectError: true},
+		{expr: "(let ((x 1 2)) x)", expectError: true},
+		{expr: "(let ((nil 1)) 1)", expectError: true},
+		{expr: "(let x x)", expectError: true},
+		{expr: "(let)", expectError: true},
+		{expr: "(let ((x (undefined-function))) x)", expectError: true},
+		{expr: "(labels ((f)) 1)", expectError: true},
+		{expr: "(labels ((1 () 1)) 1)", expectError: true},
+		{expr: "(labels ((f (1) 1)) 1)", expectError: true},
+	}
+
+	runEvalCases(t, cases)
+} // func TestLet(t *testing.T)
+
+func TestLetScope(t *testing.T) {
+	var qi = quietInterpreter()
+	var root = qi.Env.scope
+
+	for _, src := range []string{"(let ((x 1)) x)", "(let ((x 1)) (undefined-function x))", "(labels ((f () 1)) (f))"} {
+		var form, err = read(src)
+
+		if err != nil {
+			t.Fatalf("Cannot parse %q: %s", src, err.Error())
+		}
+
+		_, _ = qi.Eval(form)
+
+		if qi.Env.scope != root {
+			t.Errorf("Scope was not restored after evaluating %s", src)
+		}
+	}
+} // func TestLetScope(t *testing.T)
@@ -0,0 +1,92 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/15_set_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 16:40:13 krylon>
+
+package interpreter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+func TestEnvironmentAssign(t *testing.T) {
+	var (
+		err error
+		val parser.LispValue
+		env = MakeEnvironment()
+	)
+
+	env.Define("x", parser.Integer{Int: 1})
+	env.Push()
+	env.Set(sym("y"), parser.Integer{Int: 2})
+	env.Push()
+
+	if err = env.Assign(sym("x"), parser.Integer{Int: 10}); err != nil {
+		t.Errorf("Cannot assign to global x: %s", err.Error())
+	} else if err = env.Assign(sym("y"), parser.Integer{Int: 20}); err != nil {
+		t.Errorf("Cannot assign to outer y: %s", err.Error())
+	} else if err = env.Assign(sym("z"), parser.Integer{Int: 30}); !errors.Is(err, ErrUnbound) {
+		t.Errorf("Assigning to unbound z should fail with ErrUnbound, not %v", err)
+	} else if _, ok := env.scope.bindings[sym("y").Atom()]; ok {
+		t.Error("Assign created a binding in the current scope")
+	}
+
+	env.Pop()
+
+	if val, _ = env.Lookup(sym("y")); !val.Equal(parser.Integer{Int: 20}) {
+		t.Errorf("Unexpected value of y: %s", val)
+	}
+
+	env.Pop()
+
+	if val, _ = env.Lookup(sym("x")); !val.Equal(parser.Integer{Int: 10}) {
+		t.Errorf("Unexpected value of x: %s", val)
+	}
+} // func TestEnvironmentAssign(t *testing.T)
+
+func TestSet(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(defparameter set-global 1)", result: "SET-GLOBAL"},
+		{expr: "(set! set-global (+ set-global 1))", result: "2"},
+		{expr: "set-global", result: "2"},
+		{expr: "(defun bump-global () (set! set-global (* set-global 10)))", result: "BUMP-GLOBAL"},
+		{expr: "(bump-global)", result: "20"},
+		{expr: "set-global", result: "20"},
+		{expr: "(let ((n 0)) (defun next-count () (set! n (+ n 1))))", result: "NEXT-COUNT"},
+		{expr: "(next-count)", result: "1"},
+		{expr: "(next-count)", result: "2"},
+		{expr: "n", expectError: true},
+		{expr: "(defun shadow-set (set-global) (set! set-global 5) set-global)", result: "SHADOW-SET"},
+		{expr: "(shadow-set 0)", result: "5"},
+		{expr: "set-global", result: "20"},
+		{expr: "(let ((x 1)) (let ((y 2)) (set! x y)) x)", result: "2"},
+		{expr: "(handler-case (set! no-such-variable 1) (unbound-variable () 'unbound))", result: "UNBOUND"},
+		{expr: "(set! nil 1)", expectError: true},
+		{expr: "(set! :key 1)", expectError: true},
+		{expr: "(set! 1 1)", expectError: true},
+		{expr: "(set! set-global)", expectError: true},
+		{expr: "(defvar set-defvar (+ 1 2) \"A variable.\")", result: "SET-DEFVAR"},
+		{expr: "(defvar set-defvar (undefined-function))", result: "SET-DEFVAR"},
+		{expr: "set-defvar", result: "3"},
+		{expr: "(defparameter set-defvar 4)", result: "SET-DEFVAR"},
+		{expr: "set-defvar", result: "4"},
+		{expr: "(var set-defvar 5)", result: "SET-DEFVAR"},
+		{expr: "set-defvar", result: "5"},
+		{expr: "(defvar set-unbound)", result: "SET-UNBOUND"},
+		{expr: "set-unbound", result: "NIL"},
+		{expr: "(let ((set-inner 1)) (defparameter set-inner 2) set-inner)", result: "1"},
+		{expr: "set-inner", result: "2"},
+		{expr: "(defun define-inside () (defvar set-deep 'deep))", result: "DEFINE-INSIDE"},
+		{expr: "(define-inside)", result: "SET-DEEP"},
+		{expr: "set-deep", result: "DEEP"},
+		{expr: "(defvar)", expectError: true},
+		{expr: "(defvar t 1)", expectError: true},
+		{expr: "(defvar set-doc 1 2)", expectError: true},
+	}
+
+	runEvalCases(t, cases)
+} // func TestSet(t *testing.T)
@@ -0,0 +1,145 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/16_special_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 17:58:30 krylon>
+
+package interpreter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+func TestSpecialVariable(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(defvar *level* 0)", result: "*LEVEL*"},
+		{expr: "(defun get-level () *level*)", result: "GET-LEVEL"},
+		{expr: "(let ((*level* 1)) (get-level))", result: "1"},
+		{expr: "*level*", result: "0"},
+		{expr: "(let ((*level* 1)) (let ((*level* 2)) (get-level)))", result: "2"},
+		{expr: "(let ((*level* 1)) (let ((*level* 2)) 'inner) (get-level))", result: "1"},
+		{expr: "(let* ((*level* 4) (y (get-level))) y)", result: "4"},
+		{expr: "(let ((*level* 1)) (set! *level* 3) (get-level))", result: "3"},
+		{expr: "*level*", result: "0"},
+		{expr: "(defun with-level (*level*) (get-level))", result: "WITH-LEVEL"},
+		{expr: "(with-level 7)", result: "7"},
+		{expr: "(defun with-optional-level (&optional (*level* 8)) (get-level))", result: "WITH-OPTIONAL-LEVEL"},
+		{expr: "(with-optional-level)", result: "8"},
+		{expr: "*level*", result: "0"},
+		{expr: "(handler-case (let ((*level* 5)) (error \"boom\")) (error () *level*))", result: "0"},
+		{expr: "(ignore-errors (with-level (undefined-function)))", result: "NIL"},
+		{expr: "*level*", result: "0"},
+		{expr: "(defun make-level-getter () (let ((*level* 9)) (lambda () *level*)))", result: "MAKE-LEVEL-GETTER"},
+		{expr: "((make-level-getter))", result: "0"},
+		{expr: "(defun count-level (n) (if (< n 1) *level* (let ((*level* n)) (count-level (+ n -1)))))", result: "COUNT-LEVEL"},
+		{expr: "(count-level 3)", result: "1"},
+		{expr: "*level*", result: "0"},
+		{expr: "(defvar lexical-level 0)", result: "LEXICAL-LEVEL"},
+		{expr: "(defun get-lexical-level () lexical-level)", result: "GET-LEXICAL-LEVEL"},
+		{expr: "(let ((lexical-level 1)) (get-lexical-level))", result: "0"},
+	}
+
+	runEvalCases(t, cases)
+} // func TestSpecialVariable(t *testing.T)
+
+func TestSpecialVariableCompiled(t *testing.T) {
+	var (
+		err  error
+		form parser.LispValue
+		res  parser.LispValue
+		qi   = quietInterpreter()
+		defs = []string{
+			"(defvar *mode* 'normal)",
+			"(defun get-mode () *mode*)",
+		}
+	)
+
+	for _, src := range defs {
+		if form, err = read(src); err != nil {
+			t.Fatalf("Cannot parse %q: %s", src, err.Error())
+		} else if _, err = qi.EvalCompiled(form); err != nil {
+			t.Fatalf("Cannot evaluate %q: %s", src, err.Error())
+		}
+	}
+
+	if _, ok := qi.Env.root().bindings[sym("get-mode").Atom()].(*Closure); !ok {
+		t.Error("GET-MODE was not compiled")
+	}
+
+	if form, err = read("(let ((*mode* 'special)) (get-mode))"); err != nil {
+		t.Fatalf("Cannot parse LET: %s", err.Error())
+	} else if res, err = qi.EvalCompiled(form); err != nil {
+		t.Errorf("Error evaluating %s: %s", form, err.Error())
+	} else if res.String() != "SPECIAL" {
+		t.Errorf("Compiled function does not see dynamic binding: %s", res)
+	}
+
+	if form, err = read("(defun bind-mode (*mode*) (get-mode))"); err != nil {
+		t.Fatalf("Cannot parse DEFUN: %s", err.Error())
+	} else if _, err = qi.Compile(form); !errors.Is(err, ErrNotCompilable) {
+		t.Errorf("Function binding a special variable should not be compiled: %v", err)
+	}
+} // func TestSpecialVariableCompiled(t *testing.T)
+
+func TestSpecialVariableTailCall(t *testing.T) {
+	const iterations = "10000"
+
+	var (
+		err   error
+		form  parser.LispValue
+		res   parser.LispValue
+		depth int
+		qi    = quietInterpreter()
+		defs  = []string{
+			"(defvar *depth* 0)",
+			"(defun deepen (*depth* n) (dynamic-depth) (if (< n 1) *depth* (deepen (+ *depth* 1) (+ n -1))))",
+			"(defun deepen-let (n) (let ((*depth* n)) (dynamic-depth) (if (< n 1) 'done (deepen-let (+ n -1)))))",
+		}
+		cases = []struct {
+			expr   string
+			result string
+		}{
+			{"(deepen 0 " + iterations + ")", iterations},
+			{"*depth*", "0"},
+			{"(deepen-let " + iterations + ")", "DONE"},
+			{"*depth*", "0"},
+		}
+	)
+
+	qi.Env.Define("dynamic-depth", NewBuiltin("dynamic-depth", func(args []parser.LispValue) (parser.LispValue, error) {
+		depth = max(depth, len(qi.dynamic))
+		return sym("nil"), nil
+	}))
+
+	for _, src := range defs {
+		if form, err = read(src); err != nil {
+			t.Fatalf("Cannot parse %q: %s", src, err.Error())
+		} else if _, err = qi.Eval(form); err != nil {
+			t.Fatalf("Cannot evaluate %q: %s", src, err.Error())
+		}
+	}
+
+	for _, c := range cases {
+		depth = 0
+
+		if form, err = read(c.expr); err != nil {
+			t.Fatalf("Cannot parse %q: %s", c.expr, err.Error())
+		} else if res, err = qi.Eval(form); err != nil {
+			t.Errorf("Error evaluating %q: %s", c.expr, err.Error())
+		} else if res.String() != c.result {
+			t.Errorf("Unexpected result for %q: expected %s, got %s",
+				c.expr,
+				c.result,
+				res)
+		} else if depth > 1 {
+			t.Errorf("Tail calls in %q saved %d values of special variables",
+				c.expr,
+				depth)
+		} else if len(qi.dynamic) != 0 {
+			t.Errorf("Special variables were not restored after %q", c.expr)
+		}
+	}
+} // func TestSpecialVariableTailCall(t *testing.T)
@@ -0,0 +1,67 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/17_cond_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 17:21:09 krylon>
+
+package interpreter
+
+import "testing"
+
+func TestCond(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(cond ((< 2 1) 'a) ((< 1 2) 'b) (t 'c))", result: "B"},
+		{expr: "(cond ((< 2 1) 'a))", result: "NIL"},
+		{expr: "(cond)", result: "NIL"},
+		{expr: "(cond (42))", result: "42"},
+		{expr: "(cond ('(1 2)))", result: "(1 2)"},
+		{expr: "(cond (t 1 2 3))", result: "3"},
+		{expr: "(cond (nil (undefined-function)) (t 'ok))", result: "OK"},
+		{expr: "(cond (t))", result: "T"},
+		{expr: "(cond x)", expectError: true},
+		{expr: "(cond ())", expectError: true},
+		{expr: "(case 2 (1 'one) ((2 3) 'two-or-three) (otherwise 'many))", result: "TWO-OR-THREE"},
+		{expr: "(case 7 (1 'one) (otherwise 'many))", result: "MANY"},
+		{expr: "(case 'b ((a) 1) ((b) 2) (t 3))", result: "2"},
+		{expr: "(case 'z ((a) 1))", result: "NIL"},
+		{expr: "(case 1.0 (1 'int) (1.0 'float))", result: "FLOAT"},
+		{expr: "(case nil (nil 'never) ((nil) 'nil-key))", result: "NIL-KEY"},
+		{expr: "(case '() ((nil) 'empty))", result: "EMPTY"},
+		{expr: "(case \"a\" ((\"a\") 'string) (t 'not-eql))", result: "NOT-EQL"},
+		{expr: "(case (+ 1 1) (2))", result: "NIL"},
+		{expr: "(case)", expectError: true},
+		{expr: "(case 1 x)", expectError: true},
+		{expr: "(when (< 1 2) 'a 'b)", result: "B"},
+		{expr: "(when (< 2 1) (undefined-function))", result: "NIL"},
+		{expr: "(when t)", result: "NIL"},
+		{expr: "(unless (< 2 1) 'a)", result: "A"},
+		{expr: "(unless t (undefined-function))", result: "NIL"},
+		{expr: "(when)", expectError: true},
+	}
+
+	runEvalCases(t, cases)
+} // func TestCond(t *testing.T)
+
+func TestAndOrNot(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(and)", result: "T"},
+		{expr: "(and 1 2 3)", result: "3"},
+		{expr: "(and 1 nil (undefined-function))", result: "NIL"},
+		{expr: "(null (and 1 '()))", result: "T"},
+		{expr: "(or)", result: "NIL"},
+		{expr: "(or nil 2 (undefined-function))", result: "2"},
+		{expr: "(or nil '(a b))", result: "(A B)"},
+		{expr: "(or '(a b) 1)", result: "(A B)"},
+		{expr: "(or nil nil)", result: "NIL"},
+		{expr: "(or (undefined-function) t)", expectError: true},
+		{expr: "(not nil)", result: "T"},
+		{expr: "(not '())", result: "T"},
+		{expr: "(not 0)", result: "NIL"},
+		{expr: "(null (and 1 nil))", result: "T"},
+		{expr: "(not)", expectError: true},
+		{expr: "(not 1 2)", expectError: true},
+		{expr: "(labels ((count (n) (and t (or nil (cond ((< n 1) 'done) (t (count (+ n -1)))))))) (count 1000))", result: "DONE"},
+	}
+
+	runEvalCases(t, cases)
+} // func TestAndOrNot(t *testing.T)
@@ -0,0 +1,113 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/18_loop_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 19:12:40 krylon>
+
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBlock(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(block done 1 (return-from done 2) 3)", result: "2"},
+		{expr: "(block done 1 2)", result: "2"},
+		{expr: "(block done)", result: "NIL"},
+		{expr: "(block outer (block inner (return-from outer 'o)) 'after)", result: "O"},
+		{expr: "(block nil (return 5) 6)", result: "5"},
+		{expr: "(block nil (return))", result: "NIL"},
+		{expr: "(block b (handler-case (return-from b 'escaped) (error () 'caught)))", result: "ESCAPED"},
+		{expr: "(block b (ignore-errors (return-from b 'escaped)) 'not-here)", result: "ESCAPED"},
+		{expr: "(block b (funcall (lambda () (return-from b 'from-closure))) 'not-here)", result: "FROM-CLOSURE"},
+		{expr: "(block b (mapcar (lambda (x) (when (eql x 2) (return-from b x))) '(1 2 3)))", result: "2"},
+		{expr: "(block nil (let ((x 1)) (dolist (y '(a b)) (return-from nil (list x y)))))", result: "(1 A)"},
+		{expr: "(defun leave-block () (return-from caller 'from-callee))", result: "LEAVE-BLOCK"},
+		{expr: "(block caller (leave-block) 'not-here)", expectError: true},
+		{expr: "(defun return-one () (return 1))", result: "RETURN-ONE"},
+		{expr: "(dotimes (i 3) (return-one))", expectError: true},
+		{expr: "(handler-case (dotimes (i 3) (return-one)) (control-error () 'control))", result: "CONTROL"},
+		{expr: "(funcall (block b (lambda () (return-from b 1))))", expectError: true},
+		{expr: "(return-from nowhere 1)", expectError: true},
+		{expr: "(return 1)", expectError: true},
+		{expr: "(block 1 2)", expectError: true},
+		{expr: "(return-from)", expectError: true},
+		{expr: "(return 1 2)", expectError: true},
+	}
+
+	runEvalCases(t, cases)
+} // func TestBlock(t *testing.T)
+
+func TestLoops(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(let ((i 0) (acc 0)) (while (< i 5) (set! acc (+ acc i)) (set! i (+ i 1))) acc)", result: "10"},
+		{expr: "(while nil (undefined-function))", result: "NIL"},
+		{expr: "(let ((i 0)) (while t (set! i (+ i 1)) (if (< i 10) nil (return i))))", result: "10"},
+		{expr: "(let ((acc 0)) (dotimes (i 5) (set! acc (+ acc i))) acc)", result: "10"},
+		{expr: "(dotimes (i 3 i))", result: "3"},
+		{expr: "(dotimes (i 0 'none) (undefined-function))", result: "NONE"},
+		{expr: "(dotimes (i 10) (if (< i 4) nil (return i)))", result: "4"},
+		{expr: "(let ((acc 0)) (dolist (x '(1 2 3) acc) (set! acc (+ acc x))))", result: "6"},
+		{expr: "(dolist (x '(1 2 3) x))", result: "NIL"},
+		{expr: "(dolist (x '(a b c)) (case x (b (return x))))", result: "B"},
+		{expr: "(let ((f nil)) (dolist (x '(1 2)) (when (< x 2) (set! f (lambda () x)))) (f))", result: "1"},
+		{expr: "(dotimes (i 2) i)", result: "NIL"},
+		{expr: "(dotimes (i 'a))", expectError: true},
+		{expr: "(dolist (x 1))", expectError: true},
+		{expr: "(dolist x)", expectError: true},
+		{expr: "(dotimes (1 2))", expectError: true},
+		{expr: "(while)", expectError: true},
+	}
+
+	runEvalCases(t, cases)
+} // func TestLoops(t *testing.T)
+
+func TestLoopMacro(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(loop for x in '(1 2 3) collect (* x x))", result: "(1 4 9)"},
+		{expr: "(loop for i from 1 to 10 sum i)", result: "55"},
+		{expr: "(loop for i from 0 to 10 by 5 collect i)", result: "(0 5 10)"},
+		{expr: "(loop for x in '(1 2 3 4 5 6) when (< 3 x) collect x)", result: "(4 5 6)"},
+		{expr: "(loop for x in '(10 20 30) for i from 1 collect (* i x))", result: "(10 40 90)"},
+		{expr: "(loop for x in '(1 2.5) sum x)", result: "3.5"},
+		{expr: "(loop for x in '() collect x)", result: "NIL"},
+		{expr: "(loop for i from 1 to 3 do (set! i i))", result: "NIL"},
+		{expr: "(let ((acc 0)) (loop for i from 1 to 3 do (set! acc (+ acc i)) finally (return acc)))", result: "6"},
+		{expr: "(loop for i from 1 when (< 4 i) do (return i))", result: "5"},
+		{expr: "(let ((i 0)) (loop (set! i (+ i 1)) (if (< i 3) nil (return i))))", result: "3"},
+		{expr: "(loop for x in '(1 2) collect x sum x)", expectError: true},
+		{expr: "(loop for x on '(1 2) collect x)", expectError: true},
+		{expr: "(loop for x in)", expectError: true},
+		{expr: "(loop for x in 5 collect x)", expectError: true},
+		{expr: "(loop for i from 'a to 3 collect i)", expectError: true},
+		{expr: "(loop for i from 1 to 3 by 0 collect i)", expectError: true},
+		{expr: "(loop repeat 3 collect 1)", expectError: true},
+		{expr: "(loop for x in '(1 a) sum x)", expectError: true},
+		{expr: "(loop when t)", expectError: true},
+	}
+
+	runEvalCases(t, cases)
+} // func TestLoopMacro(t *testing.T)
+
+func TestLoopLimits(t *testing.T) {
+	var (
+		err error
+		qi  = quietInterpreter()
+		src = []string{"(loop)", "(while t)", "(loop for i from 0 do (+ i 1))"}
+	)
+
+	qi.Limits.MaxSteps = 10000
+
+	for _, s := range src {
+		var form, perr = read(s)
+
+		if perr != nil {
+			t.Fatalf("Cannot parse %q: %s", s, perr.Error())
+		} else if _, err = qi.EvalContext(context.Background(), form); !errors.Is(err, ErrStepLimit) {
+			t.Errorf("Evaluating %s should have hit the step limit, got %v", s, err)
+		}
+	}
+} // func TestLoopLimits(t *testing.T)
@@ -0,0 +1,46 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/19_cons_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 21:32:40 krylon>
+
+package interpreter
+
+import "testing"
+
+func TestCons(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(cons 1 2)", result: "(1 . 2)"},
+		{expr: "(cons 1 '(2 3))", result: "(1 2 3)"},
+		{expr: "(cons 1 nil)", result: "(1)"},
+		{expr: "(cons 1 (cons 2 3))", result: "(1 2 . 3)"},
+		{expr: "(car (cons 1 2))", result: "1"},
+		{expr: "(cdr (cons 1 2))", result: "2"},
+		{expr: "(car '(a b c))", result: "A"},
+		{expr: "(cdr '(a b c))", result: "(B C)"},
+		{expr: "(cdr '(a))", result: "NIL"},
+		{expr: "(car nil)", result: "NIL"},
+		{expr: "(cdr '())", result: "NIL"},
+		{expr: "'(a . b)", result: "(A . B)"},
+		{expr: "'(a b . c)", result: "(A B . C)"},
+		{expr: "'(a . (b c))", result: "(A B C)"},
+		{expr: "(cdr '(a b . c))", result: "(B . C)"},
+		{expr: "(list)", result: "NIL"},
+		{expr: "(list 1 (+ 1 1) 3)", result: "(1 2 3)"},
+		{expr: "(let ((x (list 1 2))) (rplaca x 'a) x)", result: "(A 2)"},
+		{expr: "(let ((x (list 1 2))) (rplacd x 3) x)", result: "(1 . 3)"},
+		{expr: "(let* ((x (list 1 2)) (y x)) (rplacd y nil) x)", result: "(1)"},
+		{expr: "(rplaca (cons 1 2) 3)", result: "(3 . 2)"},
+		{expr: "(cons 1)", expectError: true},
+		{expr: "(car 1)", expectError: true},
+		{expr: "(cdr \"abc\")", expectError: true},
+		{expr: "(car '(1) '(2))", expectError: true},
+		{expr: "(rplaca nil 1)", expectError: true},
+		{expr: "(rplacd 'a 1)", expectError: true},
+		{expr: "(1 . 2)", expectError: true},
+		{expr: "(+ 1 . 2)", expectError: true},
+		{expr: "(list 1 2 . 3)", expectError: true},
+	}
+
+	runEvalCases(t, cases)
+} // func TestCons(t *testing.T)
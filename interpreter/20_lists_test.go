@@ -0,0 +1,146 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/20_lists_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 23:05:12 krylon>
+
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestListAccess(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(cadr '(1 2 3))", result: "2"},
+		{expr: "(cddr '(1 2 3))", result: "(3)"},
+		{expr: "(caar '((a b) c))", result: "A"},
+		{expr: "(cdar '((a b) c))", result: "(B)"},
+		{expr: "(caddr '(1 2 3))", result: "3"},
+		{expr: "(cadddr '(1 2 3 4))", result: "4"},
+		{expr: "(cddddr '(1 2 3 4 5))", result: "(5)"},
+		{expr: "(cadr '(1))", result: "NIL"},
+		{expr: "(cadr 1)", expectError: true},
+		{expr: "(caddddr '(1 2 3 4 5))", expectError: true},
+		{expr: "(nth 0 '(a b c))", result: "A"},
+		{expr: "(nth 2 '(a b c))", result: "C"},
+		{expr: "(nth 5 '(a b c))", result: "NIL"},
+		{expr: "(nth -1 '(a b c))", expectError: true},
+		{expr: "(nth 'a '(a b c))", expectError: true},
+		{expr: "(nthcdr 0 '(a b c))", result: "(A B C)"},
+		{expr: "(nthcdr 2 '(a b c))", result: "(C)"},
+		{expr: "(nthcdr 1 '(a . b))", result: "B"},
+		{expr: "(nthcdr 3 '(a b c))", result: "NIL"},
+		{expr: "(length '(1 2 3))", result: "3"},
+		{expr: "(length nil)", result: "0"},
+		{expr: "(length \"Grüße\")", result: "5"},
+		{expr: "(length '(1 . 2))", expectError: true},
+		{expr: "(length 42)", expectError: true},
+		{expr: "(last '(1 2 3))", result: "(3)"},
+		{expr: "(last '(1 2 3) 2)", result: "(2 3)"},
+		{expr: "(last '(1 2 3) 7)", result: "(1 2 3)"},
+		{expr: "(last '(1 2 3) 0)", result: "NIL"},
+		{expr: "(last '(1 2 . 3))", result: "(2 . 3)"},
+		{expr: "(last nil)", result: "NIL"},
+		{expr: "(last 'a)", expectError: true},
+		{expr: "(member 2 '(1 2 3))", result: "(2 3)"},
+		{expr: "(member 'x '(1 2 3))", result: "NIL"},
+		{expr: "(member 1.0 '(1 2 3))", result: "NIL"},
+		{expr: "(member 1 '(0 . 1))", expectError: true},
+		{expr: "(assoc 'b '((a . 1) (b . 2)))", result: "(B . 2)"},
+		{expr: "(assoc 'c '((a . 1) nil (b . 2)))", result: "NIL"},
+		{expr: "(assoc 'a '(x))", expectError: true},
+	}
+
+	runEvalCases(t, cases)
+} // func TestListAccess(t *testing.T)
+
+func TestListBuild(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(append)", result: "NIL"},
+		{expr: "(append '(1 2) '(3) nil '(4 5))", result: "(1 2 3 4 5)"},
+		{expr: "(append '(1) 2)", result: "(1 . 2)"},
+		{expr: "(append nil 'a)", result: "A"},
+		{expr: "(append '(1 . 2) '(3))", expectError: true},
+		{expr: "(let* ((a (list 1 2)) (b (append a '(3)))) (rplaca a 'x) b)", result: "(1 2 3)"},
+		{expr: "(reverse '(1 2 3))", result: "(3 2 1)"},
+		{expr: "(reverse nil)", result: "NIL"},
+		{expr: "(let* ((a (list 1 2)) (b (reverse a))) (list a b))", result: "((1 2) (2 1))"},
+		{expr: "(reverse 'a)", expectError: true},
+	}
+
+	runEvalCases(t, cases)
+} // func TestListBuild(t *testing.T)
+
+func TestListHigherOrder(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(mapcar (lambda (x) (* x x)) '(1 2 3))", result: "(1 4 9)"},
+		{expr: "(mapcar '+ '(1 2 3) '(10 20))", result: "(11 22)"},
+		{expr: "(mapcar 'car '((a 1) (b 2)))", result: "(A B)"},
+		{expr: "(mapcar 'cadr '((a 1) (b 2)))", result: "(1 2)"},
+		{expr: "(mapcar (lambda (x) x) nil)", result: "NIL"},
+		{expr: "(labels ((twice (x) (* 2 x))) (mapcar twice '(1 2)))", result: "(2 4)"},
+		{expr: "(labels ((twice (x) (* 2 x))) (mapcar 'twice '(1 2)))", result: "(2 4)"},
+		{expr: "(mapcar 'undefined-function '(1))", expectError: true},
+		{expr: "(mapcar 42 '(1))", expectError: true},
+		{expr: "(mapcar '+)", expectError: true},
+		{expr: "(remove-if (lambda (x) (< x 3)) '(1 5 2 4))", result: "(5 4)"},
+		{expr: "(remove-if-not (lambda (x) (< x 3)) '(1 5 2 4))", result: "(1 2)"},
+		{expr: "(remove-if 'null '(1 nil 2 nil))", result: "(1 2)"},
+		{expr: "(remove-if 'null nil)", result: "NIL"},
+		{expr: "(reduce '+ '(1 2 3 4))", result: "10"},
+		{expr: "(reduce '- '(10 1 2))", result: "7"},
+		{expr: "(reduce '+ nil)", result: "0"},
+		{expr: "(reduce '+ '(5))", result: "5"},
+		{expr: "(reduce (lambda (acc x) (cons x acc)) '(1 2 3) :initial-value nil)", result: "(3 2 1)"},
+		{expr: "(reduce '+ '(1 2) :initial 3)", expectError: true},
+		{expr: "(reduce '+ '(1 2) :initial-value)", expectError: true},
+		{expr: "(sort '(3 1 2) '<)", result: "(1 2 3)"},
+		{expr: "(sort '(3 1 2) (lambda (a b) (> a b)))", result: "(3 2 1)"},
+		{expr: "(sort '((b 1) (a 2) (c 1)) (lambda (x y) (< (cadr x) (cadr y))))", result: "((B 1) (C 1) (A 2))"},
+		{expr: "(let* ((a (list 2 1)) (b (sort a '<))) (list a b))", result: "((2 1) (1 2))"},
+		{expr: "(sort '(1 a) '<)", expectError: true},
+		{expr: "(funcall '+ 1 2)", result: "3"},
+		{expr: "(funcall (lambda () 'ok))", result: "OK"},
+		{expr: "(funcall)", expectError: true},
+		{expr: "(apply '+ 1 2 '(3 4))", result: "10"},
+		{expr: "(apply (lambda (&rest xs) xs) '(a b))", result: "(A B)"},
+		{expr: "(apply '+ nil)", result: "0"},
+		{expr: "(apply '+ 1 2)", expectError: true},
+		{expr: "(apply '+)", expectError: true},
+		{expr: "(mapcar + '(1 2) '(3 4))", result: "(4 6)"},
+		{expr: "(mapcar car '((a 1) (b 2)))", result: "(A B)"},
+		{expr: "(reduce + '(1 2 3))", result: "6"},
+		{expr: "(sort '(3 1 2) <)", result: "(1 2 3)"},
+		{expr: "(apply list 1 '(2 3))", result: "(1 2 3)"},
+		{expr: "(funcall cons 1 2)", result: "(1 . 2)"},
+		{expr: "(funcall funcall + 1 2)", result: "3"},
+		{expr: "(funcall 'gethash 'a #h((a . 1)))", result: "1"},
+		{expr: "(multiple-value-list (funcall gethash 'b #h((a . 1))))", result: "(NIL NIL)"},
+		{expr: "(let ((f car)) (f '(1 2)))", result: "1"},
+		{expr: "+", result: "#<BUILTIN +>"},
+		{expr: "(let ((list '(1 2))) (length list))", result: "2"},
+		{expr: "(funcall + 1 'a)", expectError: true},
+		{expr: "(funcall error \"boom\")", expectError: true},
+	}
+
+	runEvalCases(t, cases)
+} // func TestListHigherOrder(t *testing.T)
+
+func TestListConsLimit(t *testing.T) {
+	var (
+		err  error
+		qi   = quietInterpreter()
+		form = "(reverse (append '(1 2 3 4 5 6) '(7 8 9 10)))"
+	)
+
+	qi.Limits.MaxConses = 8
+
+	if f, perr := read(form); perr != nil {
+		t.Fatalf("Cannot parse %q: %s", form, perr.Error())
+	} else if _, err = qi.EvalContext(context.Background(), f); !errors.Is(err, ErrConsLimit) {
+		t.Errorf("Evaluating %s should have hit the cons limit, got %v", form, err)
+	}
+} // func TestListConsLimit(t *testing.T)
@@ -0,0 +1,59 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/21_equal_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 23:58:30 krylon>
+
+package interpreter
+
+import "testing"
+
+func TestEquality(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(eq 'a 'a)", result: "T"},
+		{expr: "(eq 'a 'b)", result: "NIL"},
+		{expr: "(eq nil '())", result: "T"},
+		{expr: "(eq '() nil)", result: "T"},
+		{expr: "(eq :key :key)", result: "T"},
+		{expr: "(eq 1 1)", result: "T"},
+		{expr: "(eq 1.5 1.5)", result: "T"},
+		{expr: "(eq 1 1.0)", result: "NIL"},
+		{expr: "(eq \"a\" \"a\")", result: "NIL"},
+		{expr: "(let ((s \"a\")) (eq s s))", result: "T"},
+		{expr: "(let ((s \"a\") (u \"a\")) (eq s u))", result: "NIL"},
+		{expr: "(let* ((s \"a\") (l (list s))) (eq s (car l)))", result: "T"},
+		{expr: "(eq '(1) '(1))", result: "NIL"},
+		{expr: "(let ((x (list 1))) (eq x x))", result: "T"},
+		{expr: "(let ((x (list 1 2))) (eq (cdr x) (cdr x)))", result: "T"},
+		{expr: "(let ((f (lambda (x) x))) (eq f f))", result: "T"},
+		{expr: "(eq (lambda (x) x) (lambda (x) x))", result: "NIL"},
+		{expr: "(eql 1 1)", result: "T"},
+		{expr: "(eql 1.5 1.5)", result: "T"},
+		{expr: "(eql 1 1.0)", result: "NIL"},
+		{expr: "(eql 0.0 0.0)", result: "T"},
+		{expr: "(eql 0.0 -0.0)", result: "NIL"},
+		{expr: "(= 0.0 -0.0)", result: "T"},
+		{expr: "(equal 0.0 -0.0)", result: "NIL"},
+		{expr: "(eql 'a 'a)", result: "T"},
+		{expr: "(eql nil '())", result: "T"},
+		{expr: "(eql \"a\" \"a\")", result: "NIL"},
+		{expr: "(let ((s \"a\")) (eql s s))", result: "T"},
+		{expr: "(let ((s \"a\")) (member s (list \"a\" s)))", result: "(\"a\")"},
+		{expr: "(eql '(1) '(1))", result: "NIL"},
+		{expr: "(let ((x (cons 1 2))) (eql x x))", result: "T"},
+		{expr: "(equal '(1 (2 \"x\") . 3) '(1 (2 \"x\") . 3))", result: "T"},
+		{expr: "(equal '(1 2) '(1 2 3))", result: "NIL"},
+		{expr: "(equal \"abc\" \"abc\")", result: "T"},
+		{expr: "(equal \"abc\" \"ABC\")", result: "NIL"},
+		{expr: "(equal 1 1.0)", result: "NIL"},
+		{expr: "(equal nil '())", result: "T"},
+		{expr: "(equal '(nil) '(()))", result: "T"},
+		{expr: "(equal 'a \"A\")", result: "NIL"},
+		{expr: "(equal (lambda (x) x) (lambda (x) x))", result: "NIL"},
+		{expr: "(let ((f (lambda (x) x))) (equal f f))", result: "T"},
+		{expr: "(eq 1)", expectError: true},
+		{expr: "(equal 1 2 3)", expectError: true},
+	}
+
+	runEvalCases(t, cases)
+} // func TestEquality(t *testing.T)
@@ -0,0 +1,69 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/22_hashtable_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 19. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-19 02:44:09 krylon>
+
+package interpreter
+
+import "testing"
+
+func TestHashTable(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(make-hash-table)", result: "#H(:TEST EQL)"},
+		{expr: "(make-hash-table :test 'equal)", result: "#H(:TEST EQUAL)"},
+		{expr: "(let ((h (make-hash-table))) (puthash 'a 1 h) (puthash 'b '(2 3) h) h)", result: "#H(:TEST EQL (A . 1) (B 2 3))"},
+		{expr: "(let ((h (make-hash-table))) (puthash 'a 1 h) (gethash 'a h))", result: "1"},
+		{expr: "(let ((h (make-hash-table))) (puthash 'a 1 h) (+ 1 (gethash 'a h)))", result: "2"},
+		{expr: "(let ((h (make-hash-table))) (puthash 'a 1 h) (if (gethash 'b h) 'yes 'no))", result: "NO"},
+		{expr: "(let ((h (make-hash-table))) (puthash 'a nil h) (list (gethash 'a h) (hash-table-contains-p 'a h)))", result: "(NIL T)"},
+		{expr: "(gethash 'a (make-hash-table))", result: "NIL"},
+		{expr: "(multiple-value-list (gethash 'a (make-hash-table)))", result: "(NIL NIL)"},
+		{expr: "(multiple-value-list (gethash 'a (make-hash-table) 0))", result: "(0 NIL)"},
+		{expr: "(let ((h (make-hash-table))) (puthash 'a 1 h) (multiple-value-list (gethash 'a h)))", result: "(1 T)"},
+		{expr: "(let ((h (make-hash-table))) (puthash 'a nil h) (multiple-value-list (gethash 'a h)))", result: "(NIL T)"},
+		{expr: "(let ((h (make-hash-table))) (puthash 'a nil h) (multiple-value-bind (v found) (gethash 'a h) (list v found)))", result: "(NIL T)"},
+		{expr: "(let ((h (make-hash-table))) (multiple-value-bind (v found) (gethash 'a h) (list v found)))", result: "(NIL NIL)"},
+		{expr: "(let ((h (make-hash-table))) (puthash 'a 1 h) (multiple-value-list (list (gethash 'a h))))", result: "((1))"},
+		{expr: "(hash-table-contains-p 'a (make-hash-table))", result: "NIL"},
+		{expr: "(gethash 'a (make-hash-table) 0)", result: "0"},
+		{expr: "(let ((h (make-hash-table))) (puthash 'a 1 h) (gethash 'a h 0))", result: "1"},
+		{expr: "(let ((h (make-hash-table))) (setf (gethash 'k h) 42) (gethash 'k h))", result: "42"},
+		{expr: "(let ((h (make-hash-table))) (setf (gethash 'k h) 1) (setf (gethash 'k h) 2) (list (hash-table-count h) (gethash 'k h)))", result: "(1 2)"},
+		{expr: "(let ((x 1)) (setf x 2) x)", result: "2"},
+		{expr: "(setf (car x) 1)", expectError: true},
+		{expr: "(let ((h (make-hash-table))) (puthash 1 'int h) (puthash 1.0 'float h) (list (gethash 1 h) (gethash 1.0 h)))", result: "(INT FLOAT)"},
+		{expr: "(let ((h (make-hash-table))) (puthash \"a\" 1 h) (gethash \"a\" h))", result: "NIL"},
+		{expr: "(let ((h (make-hash-table :test 'equal))) (puthash \"a\" 1 h) (gethash \"a\" h))", result: "1"},
+		{expr: "(let ((h (make-hash-table :test 'equal))) (puthash '(1 (2)) 'x h) (gethash (list 1 (list 2)) h))", result: "X"},
+		{expr: "(let ((h (make-hash-table))) (puthash '(1) 'x h) (gethash (list 1) h))", result: "NIL"},
+		{expr: "(let ((h (make-hash-table)) (k (list 1))) (puthash k 'x h) (gethash k h))", result: "X"},
+		{expr: "(let ((h (make-hash-table :test 'eq))) (puthash 'a 1 h) (puthash 1 2 h) (list (gethash 'a h) (gethash 1 h)))", result: "(1 2)"},
+		{expr: "(let ((h (make-hash-table :test 'eq)) (n 42)) (puthash n 'x h) (list (gethash n h) (gethash 42 h) (gethash 42.0 h)))", result: "(X X NIL)"},
+		{expr: "(let ((h (make-hash-table :test 'eq)) (s \"key\")) (puthash s 1 h) (list (gethash s h) (gethash \"key\" h)))", result: "(1 NIL)"},
+		{expr: "(let ((h (make-hash-table)) (s \"key\")) (puthash s 1 h) (list (gethash s h) (gethash \"key\" h)))", result: "(1 NIL)"},
+		{expr: "(let ((h (make-hash-table)) (s \"key\")) (puthash s 1 h) (remhash s h))", result: "T"},
+		{expr: "(let ((h (make-hash-table))) (puthash nil 1 h) (gethash '() h))", result: "1"},
+		{expr: "(let ((h (make-hash-table))) (puthash 'a 1 h) (list (remhash 'a h) (remhash 'a h) (hash-table-count h)))", result: "(T NIL 0)"},
+		{expr: "(let ((h (make-hash-table))) (puthash 'c 1 h) (puthash 'a 2 h) (puthash 'b 3 h) (remhash 'a h) (puthash 'a 4 h) (hash-table-keys h))", result: "(C B A)"},
+		{expr: "(hash-table-keys (make-hash-table))", result: "NIL"},
+		{expr: "(let ((h (make-hash-table)) (sum 0)) (puthash 'a 1 h) (puthash 'b 2 h) (maphash (lambda (k v) (set! sum (+ sum v))) h) sum)", result: "3"},
+		{expr: "(let ((h (make-hash-table)) (ks nil)) (puthash 'a 1 h) (puthash 'b 2 h) (maphash (lambda (k v) (set! ks (cons k ks)) (remhash k h)) h) (list ks (hash-table-count h)))", result: "((B A) 0)"},
+		{expr: "(maphash (lambda (k) k) #h((a . 1)))", expectError: true},
+		{expr: "#h(:test equal (\"a\" . 1) ((1 2) . x))", result: "#H(:TEST EQUAL (\"a\" . 1) ((1 2) . X))"},
+		{expr: "(gethash '(1 2) #h(:test equal ((1 2) . x)))", result: "X"},
+		{expr: "(hash-table-count #h((a . 1) (b . 2) (a . 3)))", result: "2"},
+		{expr: "(eq #h() #h())", result: "NIL"},
+		{expr: "(let ((h (make-hash-table))) (list (eq h h) (equal h (make-hash-table))))", result: "(T NIL)"},
+		{expr: "(make-hash-table :test 'string=)", expectError: true},
+		{expr: "(make-hash-table :size 10)", expectError: true},
+		{expr: "(make-hash-table :test)", expectError: true},
+		{expr: "(gethash 'a '((a . 1)))", expectError: true},
+		{expr: "(gethash 'a (make-hash-table) 1 2)", expectError: true},
+		{expr: "(hash-table-contains-p 'a)", expectError: true},
+		{expr: "(puthash 'a 1)", expectError: true},
+		{expr: "(hash-table-count nil)", expectError: true},
+	}
+
+	runEvalCases(t, cases)
+} // func TestHashTable(t *testing.T)
@@ -0,0 +1,38 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/23_values_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 17. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-17 10:31:05 krylon>
+
+package interpreter
+
+import "testing"
+
+func TestMultipleValues(t *testing.T) {
+	var cases = []evalCase{
+		{expr: "(values 1 2 3)", result: "1"},
+		{expr: "(values)", result: "NIL"},
+		{expr: "(+ (values 1 2) 10)", result: "11"},
+		{expr: "(multiple-value-list (values 1 2 3))", result: "(1 2 3)"},
+		{expr: "(multiple-value-list 42)", result: "(42)"},
+		{expr: "(multiple-value-list (values))", result: "(NIL)"},
+		{expr: "(multiple-value-list (if t (values 1 2) 3))", result: "(1 2)"},
+		{expr: "(multiple-value-list (let ((x 1)) (values x 2)))", result: "(1 2)"},
+		{expr: "(multiple-value-list ((lambda () (values 'a 'b))))", result: "(A B)"},
+		{expr: "(multiple-value-list (list (values 1 2)))", result: "((1))"},
+		{expr: "(multiple-value-list (let ((x (values 1 2))) x))", result: "(1)"},
+		{expr: "(multiple-value-list (cons 1 (values 2 3)))", result: "((1 . 2))"},
+		{expr: "(multiple-value-bind (a b) (values 1 2) (+ a b))", result: "3"},
+		{expr: "(multiple-value-bind (a b c) (values 1 2) (list a b c))", result: "(1 2 NIL)"},
+		{expr: "(multiple-value-bind (a) (values '(x) 2) a)", result: "(X)"},
+		{expr: "(multiple-value-bind () 1 'done)", result: "DONE"},
+		{expr: "(multiple-value-bind (a) (values 1 2))", result: "NIL"},
+		{expr: "(multiple-value-bind (a 1) (values 1 2) a)", expectError: true},
+		{expr: "(multiple-value-bind a (values 1 2) a)", expectError: true},
+		{expr: "(multiple-value-bind (a))", expectError: true},
+		{expr: "(multiple-value-list)", expectError: true},
+		{expr: "(multiple-value-list 1 2)", expectError: true},
+	}
+
+	runEvalCases(t, cases)
+} // func TestMultipleValues(t *testing.T)
@@ -0,0 +1,101 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/builtin.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 01:47:09 krylon>
+
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blicero/krylisp/parser"
+	"github.com/blicero/krylisp/types"
+)
+
+// BuiltinFunc is the signature of Go functions that can be called from Lisp.
+// The arguments are evaluated before the function is called.
+type BuiltinFunc func(args []parser.LispValue) (parser.LispValue, error)
+
+// Builtin is a function implemented in Go. Lisp code calls it like any
+// other Function.
+type Builtin struct {
+	name string
+	fn   BuiltinFunc
+}
+
+// NewBuiltin creates a Builtin with the given name that calls fn.
+func NewBuiltin(name string, fn BuiltinFunc) *Builtin {
+	return &Builtin{
+		name: strings.ToUpper(name),
+		fn:   fn,
+	}
+} // func NewBuiltin(name string, fn BuiltinFunc) *Builtin
+
+func (b *Builtin) String() string {
+	return fmt.Sprintf("#<BUILTIN %s>", b.name)
+} // func (b *Builtin) String() string
+
+// Type returns the type of the receiver, i.e. types.Function
+func (b *Builtin) Type() types.Type { return types.Function }
+
+// Equal compares the receiver to another LispValue for equality.
+// Builtins are only equal to themselves.
+func (b *Builtin) Equal(other parser.LispValue) bool {
+	var o, ok = other.(*Builtin)
+
+	return ok && o == b
+} // func (b *Builtin) Equal(other parser.LispValue) bool
+
+// call calls the Go function with the given, already evaluated, arguments.
+func (b *Builtin) call(args []parser.LispValue) (parser.LispValue, error) {
+	var res, err = b.fn(args)
+
+	if err != nil {
+		return nil, fmt.Errorf("Error in call to %s: %w",
+			b.name,
+			err)
+	} else if res == nil {
+		return sym("nil"), nil
+	}
+
+	return res, nil
+} // func (b *Builtin) call(args []parser.LispValue) (parser.LispValue, error)
+
+// RegisterBuiltin makes the Go function fn callable from Lisp under the
+// given name, by binding it in the global scope of the Interpreter's
+// Environment. Special forms cannot be overridden.
+func (in *Interpreter) RegisterBuiltin(name string, fn BuiltinFunc) error {
+	var b = NewBuiltin(name, fn)
+
+	if fn == nil {
+		return fmt.Errorf("Cannot register nil as builtin %s", b.name)
+	} else if b.name == "" || b.name == "T" || b.name == "NIL" || b.name[0] == ':' {
+		return fmt.Errorf("Invalid name for builtin: %q", name)
+	} else if isSpecial(parser.Symbol{Sym: b.name}) {
+		return fmt.Errorf("Cannot register builtin %s: it is a special form", b.name)
+	}
+
+	in.Env.Define(b.name, b)
+
+	return nil
+} // func (in *Interpreter) RegisterBuiltin(name string, fn BuiltinFunc) error
+
+// defineFunctions binds the names of the primitives and functions to
+// Builtins calling them, so they can be passed to MAPCAR, FUNCALL and the
+// like, just as functions defined in Lisp. In the head of a form, the
+// special form still takes precedence.
+func (in *Interpreter) defineFunctions() {
+	for name, prim := range primitives {
+		in.Env.SetGlobal(parser.MakeSymbol(name), NewBuiltin(name, func(args []parser.LispValue) (parser.LispValue, error) {
+			return prim(name, args)
+		}))
+	}
+
+	for name, fn := range functions {
+		in.Env.SetGlobal(parser.MakeSymbol(name), NewBuiltin(name, func(args []parser.LispValue) (parser.LispValue, error) {
+			return fn(in, name, args)
+		}))
+	}
+} // func (in *Interpreter) defineFunctions()
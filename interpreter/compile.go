@@ -0,0 +1,479 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/compile.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 10:03:27 krylon>
+
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// The compiler translates a form into bytecode for the VM. It only handles
+// the subset of the language that makes up the hot paths - constants,
+// variables, function calls, IF, QUOTE, LAMBDA, DEFUN and the primitives.
+// Anything else makes the whole form uncompilable, and EvalCompiled leaves
+// it to the tree-walker.
+//
+// Parameters of functions are resolved to slots in the frame of the VM at
+// compile time. Variables of enclosing functions are captured by value when
+// a Closure is created. That is indistinguishable from capturing the
+// binding, because SET! is not compiled, so nothing can assign to a local
+// variable of compiled code. Free variables are looked up at runtime,
+// starting from the scope the Closure was created in.
+//
+// Macros are expanded when a form is compiled, not when it is run, and
+// functions that bind special variables are left to the tree-walker. When
+// a macro or a special variable is defined after a function has been
+// compiled that calls it or binds it, the compiled code no longer means
+// the same thing. Such Closures notice when they are called, and have their
+// source evaluated by the tree-walker instead.
+
+// ErrNotCompilable indicates that a form uses a feature the compiler does
+// not support.
+var ErrNotCompilable = errors.New("Form cannot be compiled")
+
+// capture describes where a Closure gets one of its captured variables
+// from when it is created: A local variable of the enclosing function, or
+// one of the captured variables of the enclosing Closure.
+type capture struct {
+	name  *parser.Atom
+	local bool
+	idx   int
+}
+
+// proto is the compiled form of a function body. The epoch is that of the
+// Interpreter when the function was compiled, calls lists the Symbols it
+// calls as global functions.
+type proto struct {
+	name     string
+	argList  []parser.LispValue
+	body     *parser.ConsCell
+	params   *lambdaList
+	nlocals  int
+	captures []capture
+	code     []instr
+	consts   []parser.LispValue
+	protos   []*proto
+	epoch    uint64
+	calls    []parser.Symbol
+}
+
+// Code is the result of compiling a top-level form.
+type Code struct {
+	main *proto
+}
+
+// String returns a disassembly of the Code.
+func (c *Code) String() string {
+	var sb strings.Builder
+
+	c.main.disassemble(&sb)
+
+	return sb.String()
+} // func (c *Code) String() string
+
+func (p *proto) disassemble(sb *strings.Builder) {
+	fmt.Fprintf(sb, "%s (%d locals, %d captured):\n",
+		p.name,
+		p.nlocals,
+		len(p.captures))
+
+	for pc, ins := range p.code {
+		fmt.Fprintf(sb, "%04d\t%-16s %d %d",
+			pc,
+			ins.op,
+			ins.a,
+			ins.b)
+
+		switch ins.op {
+		case OpConst, OpGlobal, OpFunction, OpDefun, OpPrim:
+			fmt.Fprintf(sb, "\t; %s", p.consts[ins.a])
+		}
+
+		sb.WriteString("\n")
+	}
+
+	for _, sub := range p.protos {
+		sb.WriteString("\n")
+		sub.disassemble(sb)
+	}
+} // func (p *proto) disassemble(sb *strings.Builder)
+
+// funcState keeps track of the variables visible in the function that is
+// being compiled.
+type funcState struct {
+	p      *proto
+	parent *funcState
+	locals map[*parser.Atom]int
+	upvals map[*parser.Atom]int
+}
+
+func newFuncState(p *proto, parent *funcState) *funcState {
+	return &funcState{
+		p:      p,
+		parent: parent,
+		locals: make(map[*parser.Atom]int),
+		upvals: make(map[*parser.Atom]int),
+	}
+} // func newFuncState(p *proto, parent *funcState) *funcState
+
+// resolve returns the instruction and operand to access the variable with
+// the given name. If the variable belongs to an enclosing function, it is
+// added to the captured variables of the current one, and of all the
+// functions in between. If the variable is not bound in any function, the
+// last return value is false.
+func (fs *funcState) resolve(name *parser.Atom) (Opcode, int, bool) {
+	if fs == nil {
+		return 0, 0, false
+	} else if idx, ok := fs.locals[name]; ok {
+		return OpLocal, idx, true
+	} else if idx, ok := fs.upvals[name]; ok {
+		return OpUpval, idx, true
+	}
+
+	var op, idx, ok = fs.parent.resolve(name)
+
+	if !ok {
+		return 0, 0, false
+	}
+
+	fs.p.captures = append(fs.p.captures, capture{name: name, local: op == OpLocal, idx: idx})
+	fs.upvals[name] = len(fs.p.captures) - 1
+
+	return OpUpval, fs.upvals[name], true
+} // func (fs *funcState) resolve(name *parser.Atom) (Opcode, int, bool)
+
+type compiler struct {
+	in *Interpreter
+	fs *funcState
+}
+
+// Compile translates a form into bytecode. If the form uses anything the
+// compiler does not support, the error wraps ErrNotCompilable.
+func (in *Interpreter) Compile(v parser.LispValue) (*Code, error) {
+	var (
+		main = &proto{name: "toplevel", params: new(lambdaList), epoch: in.epoch}
+		c    = &compiler{in: in, fs: newFuncState(main, nil)}
+	)
+
+	if err := c.compile(v, true); err != nil {
+		return nil, err
+	}
+
+	c.emit(OpReturn, 0, 0)
+
+	return &Code{main: main}, nil
+} // func (in *Interpreter) Compile(v parser.LispValue) (*Code, error)
+
+func notCompilable(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrNotCompilable, fmt.Sprintf(format, args...))
+} // func notCompilable(format string, args ...any) error
+
+// emit appends an instruction to the function being compiled and returns
+// its address.
+func (c *compiler) emit(op Opcode, a, b int) int {
+	var p = c.fs.p
+
+	p.code = append(p.code, instr{op: op, a: int32(a), b: int32(b)})
+
+	return len(p.code) - 1
+} // func (c *compiler) emit(op Opcode, a, b int) int
+
+// here returns the address of the next instruction, for jumps.
+func (c *compiler) here() int {
+	return len(c.fs.p.code)
+} // func (c *compiler) here() int
+
+// constant adds v to the constants of the function being compiled and
+// returns its index.
+func (c *compiler) constant(v parser.LispValue) int {
+	var p = c.fs.p
+
+	p.consts = append(p.consts, v)
+
+	return len(p.consts) - 1
+} // func (c *compiler) constant(v parser.LispValue) int
+
+// compile emits the code to evaluate v and push its value. If tail is true,
+// v is in tail position, and calls are compiled as tail calls.
+func (c *compiler) compile(v parser.LispValue, tail bool) error {
+	switch x := v.(type) {
+	case parser.Symbol:
+		if x.Sym == "T" || x.Sym == "NIL" || x.IsKeyword() {
+			c.emit(OpConst, c.constant(x), 0)
+		} else if op, idx, ok := c.fs.resolve(x.Atom()); ok {
+			c.emit(op, idx, 0)
+		} else {
+			c.emit(OpGlobal, c.constant(x), 0)
+		}
+	case parser.Integer, parser.Float, parser.String, *parser.HashTable:
+		c.emit(OpConst, c.constant(x), 0)
+	case *parser.ConsCell:
+		return c.compileList(x, tail)
+	default:
+		return notCompilable("%T", v)
+	}
+
+	return nil
+} // func (c *compiler) compile(v parser.LispValue, tail bool) error
+
+// compileList compiles a special form, macro call or function call.
+func (c *compiler) compileList(l *parser.ConsCell, tail bool) error {
+	var (
+		err   error
+		head  parser.Symbol
+		isSym bool
+	)
+
+	if head, isSym = l.Car.(parser.Symbol); isSym && isSpecial(head) {
+		return c.compileSpecial(l, tail)
+	} else if isSym {
+		if op, idx, ok := c.fs.resolve(head.Atom()); ok {
+			c.emit(op, idx, 0)
+		} else if _, isMacro := c.in.macroFor(l); isMacro {
+			var exp parser.LispValue
+
+			if exp, _, err = c.in.macroexpand1(l); err != nil {
+				return notCompilable("Error expanding %s: %s", l, err.Error())
+			}
+
+			return c.compile(exp, tail)
+		} else {
+			c.emit(OpFunction, c.constant(head), 0)
+			c.fs.p.calls = append(c.fs.p.calls, head)
+		}
+	} else if _, isList := l.Car.(*parser.ConsCell); isList {
+		if err = c.compile(l.Car, false); err != nil {
+			return err
+		}
+	} else {
+		return notCompilable("Head of list is a %T", l.Car)
+	}
+
+	var n int
+
+	if n, err = c.compileArgs(l); err != nil {
+		return err
+	} else if tail {
+		c.emit(OpTailCall, n, 0)
+	} else {
+		c.emit(OpCall, n, 0)
+	}
+
+	return nil
+} // func (c *compiler) compileList(l *parser.ConsCell, tail bool) error
+
+// compileArgs compiles the arguments of a form, i.e. all elements of the
+// list except for the first, and returns their number.
+func (c *compiler) compileArgs(l *parser.ConsCell) (int, error) {
+	var n int
+
+	for cons := l.Rest(); cons != nil; cons = cons.Rest() {
+		if err := c.compile(cons.Car, false); err != nil {
+			return 0, err
+		}
+
+		n++
+	}
+
+	return n, nil
+} // func (c *compiler) compileArgs(l *parser.ConsCell) (int, error)
+
+// compileSpecial compiles the special forms the compiler supports. Forms
+// with the wrong number of arguments are not compiled, so the tree-walker
+// reports the error when - and if - it evaluates them.
+func (c *compiler) compileSpecial(l *parser.ConsCell, tail bool) error {
+	var (
+		err  error
+		form = l.Car.(parser.Symbol).Sym
+		cnt  = l.Length()
+	)
+
+	if _, isPrim := primitives[form]; isPrim {
+		var n int
+
+		if n, err = c.compileArgs(l); err != nil {
+			return err
+		}
+
+		c.emit(OpPrim, c.constant(parser.MakeSymbol(form)), n)
+		return nil
+	}
+
+	switch form {
+	case "IF":
+		if cnt != 4 {
+			return notCompilable("IF with %d elements", cnt)
+		} else if err = c.compile(l.Rest().Car, false); err != nil {
+			return err
+		}
+
+		var jmpElse = c.emit(OpJumpIfFalse, 0, 0)
+
+		if err = c.compile(l.Rest().Rest().Car, tail); err != nil {
+			return err
+		}
+
+		var jmpEnd = c.emit(OpJump, 0, 0)
+
+		c.fs.p.code[jmpElse].a = int32(c.here())
+
+		if err = c.compile(l.Rest().Rest().Rest().Car, tail); err != nil {
+			return err
+		}
+
+		c.fs.p.code[jmpEnd].a = int32(c.here())
+	case "QUOTE":
+		if cnt != 2 {
+			return notCompilable("QUOTE with %d arguments", cnt-1)
+		}
+
+		c.emit(OpConst, c.constant(l.Rest().Car), 0)
+	case "LAMBDA":
+		var idx int
+
+		if cnt < 2 {
+			return notCompilable("LAMBDA with %d arguments", cnt-1)
+		} else if idx, err = c.compileFunction("", l.Rest().Car, l.Rest().Rest()); err != nil {
+			return err
+		}
+
+		c.emit(OpClosure, idx, 0)
+	case "DEFUN":
+		var (
+			idx  int
+			name parser.Symbol
+			ok   bool
+		)
+
+		if cnt < 3 {
+			return notCompilable("DEFUN with %d arguments", cnt-1)
+		} else if name, ok = l.Rest().Car.(parser.Symbol); !ok {
+			return notCompilable("DEFUN of a %s", l.Rest().Car.Type())
+		} else if idx, err = c.compileFunction(name.Sym, l.Rest().Rest().Car, l.Rest().Rest().Rest()); err != nil {
+			return err
+		}
+
+		c.emit(OpClosure, idx, 0)
+		c.emit(OpDefun, c.constant(name), 0)
+	default:
+		return notCompilable("Special form %s", form)
+	}
+
+	return nil
+} // func (c *compiler) compileSpecial(l *parser.ConsCell, tail bool) error
+
+// compileFunction compiles the argument list and body of a DEFUN or LAMBDA
+// form into a new proto of the current function and returns its index.
+//
+// The arguments of a call occupy the first slots of the frame: required
+// parameters, then optional ones, then the &REST list. Supplied-p
+// variables come after those. Default values are computed by a prologue
+// at the start of the function, and each parameter only becomes visible
+// after its default value, like in bindArgs.
+func (c *compiler) compileFunction(name string, argList parser.LispValue, body *parser.ConsCell) (int, error) {
+	var (
+		err    error
+		ok     bool
+		args   []parser.LispValue
+		params *lambdaList
+	)
+
+	if args, ok = listItems(argList); !ok {
+		return 0, notCompilable("Argument list %s", argList)
+	} else if params, err = parseLambdaList(args); err != nil {
+		return 0, notCompilable("Argument list %s: %s", argList, err.Error())
+	} else if params.hasKey {
+		return 0, notCompilable("&KEY parameters")
+	}
+
+	for _, a := range args {
+		if p, perr := parseParam(a); perr != nil {
+			continue
+		} else if c.in.isSpecialVar(p.name) || (p.supplied != nil && c.in.isSpecialVar(*p.supplied)) {
+			return 0, notCompilable("Special variable as parameter in %s", argList)
+		}
+	}
+
+	if body != nil && body.Rest() != nil {
+		if _, isStr := body.Car.(parser.String); isStr {
+			body = body.Rest()
+		}
+	}
+
+	var (
+		p = &proto{
+			name:    name,
+			argList: args,
+			body:    body,
+			params:  params,
+			epoch:   c.in.epoch,
+		}
+		parent = c.fs
+		fs     = newFuncState(p, parent)
+		nreq   = len(params.required)
+		nopt   = len(params.optional)
+	)
+
+	c.fs = fs
+	defer func() { c.fs = parent }()
+
+	p.nlocals = nreq + nopt
+
+	if params.rest != nil {
+		p.nlocals++
+	}
+
+	for i, s := range params.required {
+		fs.locals[s.Atom()] = i
+	}
+
+	for k, opt := range params.optional {
+		var jmp = c.emit(OpJumpIfSupplied, k, 0)
+
+		if opt.init == nil {
+			c.emit(OpConst, c.constant(sym("nil")), 0)
+		} else if err = c.compile(opt.init, false); err != nil {
+			return 0, err
+		}
+
+		c.emit(OpSetLocal, nreq+k, 0)
+		p.code[jmp].b = int32(c.here())
+		fs.locals[opt.name.Atom()] = nreq + k
+
+		if opt.supplied != nil {
+			c.emit(OpSupplied, k, 0)
+			c.emit(OpSetLocal, p.nlocals, 0)
+			fs.locals[opt.supplied.Atom()] = p.nlocals
+			p.nlocals++
+		}
+	}
+
+	if params.rest != nil {
+		fs.locals[params.rest.Atom()] = nreq + nopt
+	}
+
+	if body == nil {
+		c.emit(OpConst, c.constant(sym("nil")), 0)
+	}
+
+	for ; body != nil; body = body.Rest() {
+		if err = c.compile(body.Car, body.Rest() == nil); err != nil {
+			return 0, err
+		} else if body.Rest() != nil {
+			c.emit(OpPop, 0, 0)
+		}
+	}
+
+	c.emit(OpReturn, 0, 0)
+
+	parent.p.protos = append(parent.p.protos, p)
+
+	return len(parent.p.protos) - 1, nil
+} // func (c *compiler) compileFunction(name string, argList parser.LispValue, body *parser.ConsCell) (int, error)
@@ -0,0 +1,162 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/cond.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 17:04:31 krylon>
+
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// COND, CASE, WHEN, UNLESS, AND and OR are tailForms, like IF. They only
+// evaluate as many of their subforms as needed to decide which form ends
+// up in tail position, and return that for Eval to evaluate.
+//
+//	(cond (test body...)...)          ; a clause without body returns test
+//	(case key ((keys...) body...)...) ; keys are matched with EQL
+//	(when test body...)
+//	(unless test body...)
+//	(and forms...)                    ; (and) is T
+//	(or forms...)                     ; (or) is NIL
+//
+// In CASE, the keys of a clause can also be a single atom, and a clause
+// whose keys are T or OTHERWISE matches any key. Keys of NIL or () match
+// nothing.
+
+// quoted returns a form that evaluates to v. Values that evaluate to
+// themselves are returned as they are, anything else is wrapped in QUOTE.
+func quoted(v parser.LispValue) parser.LispValue {
+	switch x := v.(type) {
+	case parser.Integer, parser.Float, parser.String, *parser.HashTable:
+		return v
+	case parser.Symbol:
+		if x.Sym == "T" || x.Sym == "NIL" || x.IsKeyword() {
+			return v
+		}
+	}
+
+	return list(sym("quote"), v)
+} // func quoted(v parser.LispValue) parser.LispValue
+
+// reduceCond finds the first clause of a COND whose test is true.
+func (in *Interpreter) reduceCond(l *parser.ConsCell) (parser.LispValue, error) {
+	for c := l.Rest(); c != nil; c = c.Rest() {
+		var (
+			err    error
+			val    parser.LispValue
+			clause *parser.ConsCell
+			ok     bool
+		)
+
+		if clause, ok = c.Car.(*parser.ConsCell); !ok || clause.Car == nil {
+			return nil, fmt.Errorf("Malformed clause %s in COND: expected (test body...)",
+				c.Car)
+		} else if val, err = in.Eval(clause.Car); err != nil {
+			return nil, err
+		} else if !asBool(val) {
+			continue
+		} else if clause.Rest() == nil {
+			return quoted(val), nil
+		}
+
+		return in.reduceBody(clause.Rest())
+	}
+
+	return sym("nil"), nil
+} // func (in *Interpreter) reduceCond(l *parser.ConsCell) (parser.LispValue, error)
+
+// reduceCase evaluates the key of a CASE and finds the first clause that
+// lists it.
+func (in *Interpreter) reduceCase(l *parser.ConsCell) (parser.LispValue, error) {
+	var (
+		err error
+		key parser.LispValue
+	)
+
+	if l.Rest() == nil {
+		return nil, fmt.Errorf("CASE needs a key form")
+	} else if key, err = in.Eval(l.Rest().Car); err != nil {
+		return nil, err
+	}
+
+	for c := l.Rest().Rest(); c != nil; c = c.Rest() {
+		var (
+			clause *parser.ConsCell
+			ok     bool
+		)
+
+		if clause, ok = c.Car.(*parser.ConsCell); !ok || clause.Car == nil {
+			return nil, fmt.Errorf("Malformed clause %s in CASE: expected (keys body...)",
+				c.Car)
+		} else if caseMatches(clause.Car, key) {
+			return in.reduceBody(clause.Rest())
+		}
+	}
+
+	return sym("nil"), nil
+} // func (in *Interpreter) reduceCase(l *parser.ConsCell) (parser.LispValue, error)
+
+// caseMatches returns true if the keys of a CASE clause match key.
+func caseMatches(keys, key parser.LispValue) bool {
+	if s, ok := keys.(parser.Symbol); ok && (s.Sym == "T" || s.Sym == "OTHERWISE") {
+		return true
+	} else if items, ok := listItems(keys); ok {
+		for _, k := range items {
+			if parser.Eql(k, key) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return parser.Eql(keys, key)
+} // func caseMatches(keys, key parser.LispValue) bool
+
+// reduceAndOr evaluates the forms of an AND or OR up to the first one that
+// decides the result, or up to the last one, which it returns for Eval to
+// evaluate in tail position.
+func (in *Interpreter) reduceAndOr(form string, l *parser.ConsCell) (parser.LispValue, error) {
+	var isAnd = form == "AND"
+
+	if l.Rest() == nil {
+		if isAnd {
+			return sym("t"), nil
+		}
+
+		return sym("nil"), nil
+	}
+
+	var c = l.Rest()
+
+	for ; c.Rest() != nil; c = c.Rest() {
+		var val, err = in.Eval(c.Car)
+
+		if err != nil {
+			return nil, err
+		} else if isAnd && !asBool(val) {
+			return sym("nil"), nil
+		} else if !isAnd && asBool(val) {
+			return quoted(val), nil
+		}
+	}
+
+	return c.Car, nil
+} // func (in *Interpreter) reduceAndOr(form string, l *parser.ConsCell) (parser.LispValue, error)
+
+// logicalNot implements NOT and NULL, which are the same function.
+func logicalNot(form string, args []parser.LispValue) (parser.LispValue, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("Wrong number of arguments for %s: %d (expected 1)",
+			form,
+			len(args))
+	} else if asBool(args[0]) {
+		return sym("nil"), nil
+	}
+
+	return sym("t"), nil
+} // func logicalNot(form string, args []parser.LispValue) (parser.LispValue, error)
@@ -0,0 +1,467 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/condition.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 00:42:17 krylon>
+
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blicero/krylisp/parser"
+	"github.com/blicero/krylisp/types"
+)
+
+// Conditions are how kryLisp signals errors and other exceptional
+// situations. A Condition is a LispValue, so Lisp code can inspect it, and
+// it is an error, so it travels up the Go call stack like any other error
+// until a HANDLER-CASE catches it. Go errors that are not Conditions, such as
+// ErrType, are converted to one of the predefined condition types when they
+// reach a handler.
+
+// conditionHierarchy maps each of the predefined condition types to its
+// supertype. CONDITION is the root of the hierarchy.
+var conditionHierarchy = map[string]string{
+	"CONDITION":          "",
+	"SIMPLE-CONDITION":   "CONDITION",
+	"WARNING":            "CONDITION",
+	"SERIOUS-CONDITION":  "CONDITION",
+	"ERROR":              "SERIOUS-CONDITION",
+	"SIMPLE-ERROR":       "ERROR",
+	"TYPE-ERROR":         "ERROR",
+	"EVAL-ERROR":         "ERROR",
+	"UNBOUND-VARIABLE":   "ERROR",
+	"UNDEFINED-FUNCTION": "ERROR",
+	"ARITHMETIC-ERROR":   "ERROR",
+	"DIVISION-BY-ZERO":   "ARITHMETIC-ERROR",
+	"SECURITY-ERROR":     "ERROR",
+	"CONTROL-ERROR":      "ERROR",
+}
+
+// ErrUnbound indicates a reference to a Symbol that has no value.
+var ErrUnbound = errors.New("Symbol is not bound")
+
+// ErrUndefined indicates a call to a Symbol that is not bound to a function.
+var ErrUndefined = errors.New("Undefined function")
+
+// ErrControl indicates a transfer of control to an exit point that does not
+// exist, such as a RETURN-FROM outside of a block of that name.
+var ErrControl = errors.New("Control error")
+
+// errorConditions maps the sentinel errors of the interpreter to the
+// condition type they are converted to.
+var errorConditions = []struct {
+	err   error
+	ctype string
+}{
+	{ErrType, "TYPE-ERROR"},
+	{ErrEval, "EVAL-ERROR"},
+	{ErrUnbound, "UNBOUND-VARIABLE"},
+	{ErrUndefined, "UNDEFINED-FUNCTION"},
+	{ErrSecurity, "SECURITY-ERROR"},
+	{ErrControl, "CONTROL-ERROR"},
+	{ErrDivisionByZero, "DIVISION-BY-ZERO"},
+	{ErrArithmetic, "ARITHMETIC-ERROR"},
+}
+
+// Condition is an instance of one of the condition types.
+type Condition struct {
+	ctype   string
+	message string
+	cause   error
+}
+
+func (c *Condition) String() string {
+	return fmt.Sprintf("#<%s %q>", c.ctype, c.message)
+} // func (c *Condition) String() string
+
+// Type returns the type of the receiver, i.e. types.Condition
+func (c *Condition) Type() types.Type { return types.Condition }
+
+// Equal compares the receiver to another LispValue for equality.
+// Conditions are only equal to themselves.
+func (c *Condition) Equal(other parser.LispValue) bool {
+	var o, ok = other.(*Condition)
+
+	return ok && o == c
+} // func (c *Condition) Equal(other parser.LispValue) bool
+
+// Error implements the error interface.
+func (c *Condition) Error() string {
+	return c.ctype + ": " + c.message
+} // func (c *Condition) Error() string
+
+// Unwrap returns the Go error the Condition was made from, if any.
+func (c *Condition) Unwrap() error {
+	return c.cause
+} // func (c *Condition) Unwrap() error
+
+// CondType returns the name of the condition type of the receiver.
+func (c *Condition) CondType() string {
+	return c.ctype
+} // func (c *Condition) CondType() string
+
+// Message returns the message of the receiver.
+func (c *Condition) Message() string {
+	return c.message
+} // func (c *Condition) Message() string
+
+// AsCondition returns the Condition err is or wraps. Errors that are not
+// Conditions are converted to one of the predefined condition types,
+// SIMPLE-ERROR if there is no more specific one.
+func AsCondition(err error) *Condition {
+	var c *Condition
+
+	if errors.As(err, &c) {
+		return c
+	}
+
+	c = &Condition{
+		ctype:   "SIMPLE-ERROR",
+		message: err.Error(),
+		cause:   err,
+	}
+
+	for _, ec := range errorConditions {
+		if errors.Is(err, ec.err) {
+			c.ctype = ec.ctype
+			break
+		}
+	}
+
+	return c
+} // func AsCondition(err error) *Condition
+
+// conditionParent returns the supertype of the condition type ctype. The
+// second return value is false if ctype is not a condition type.
+func (in *Interpreter) conditionParent(ctype string) (string, bool) {
+	if parent, ok := in.conditionTypes[ctype]; ok {
+		return parent, true
+	}
+
+	var parent, ok = conditionHierarchy[ctype]
+
+	return parent, ok
+} // func (in *Interpreter) conditionParent(ctype string) (string, bool)
+
+// isConditionType returns true if ctype names a condition type.
+func (in *Interpreter) isConditionType(ctype string) bool {
+	var _, ok = in.conditionParent(ctype)
+
+	return ok
+} // func (in *Interpreter) isConditionType(ctype string) bool
+
+// conditionSubtype returns true if ctype is super or one of its subtypes.
+// T is a supertype of every condition type.
+func (in *Interpreter) conditionSubtype(ctype, super string) bool {
+	if super == "T" {
+		return true
+	}
+
+	for ctype != "" {
+		if ctype == super {
+			return true
+		}
+
+		ctype, _ = in.conditionParent(ctype)
+	}
+
+	return false
+} // func (in *Interpreter) conditionSubtype(ctype, super string) bool
+
+// defineCondition adds a new condition type below parent.
+func (in *Interpreter) defineCondition(name, parent string) error {
+	if _, builtin := conditionHierarchy[name]; builtin {
+		return fmt.Errorf("Cannot redefine predefined condition type %s", name)
+	} else if !in.isConditionType(parent) {
+		return fmt.Errorf("Unknown condition type %s", parent)
+	} else if in.conditionSubtype(parent, name) {
+		return fmt.Errorf("Condition type %s cannot be its own supertype", name)
+	}
+
+	if in.conditionTypes == nil {
+		in.conditionTypes = make(map[string]string)
+	}
+
+	in.conditionTypes[name] = parent
+	return nil
+} // func (in *Interpreter) defineCondition(name, parent string) error
+
+// makeCondition creates a Condition from the arguments of ERROR or SIGNAL:
+// Either a Condition, a format string and its arguments, which creates a
+// Condition of type dflt, or the name of a condition type, optionally
+// followed by a format string and its arguments.
+func (in *Interpreter) makeCondition(dflt string, args []parser.LispValue) (*Condition, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("Missing condition designator")
+	}
+
+	var (
+		err error
+		c   = &Condition{ctype: dflt}
+	)
+
+	switch datum := args[0].(type) {
+	case *Condition:
+		if len(args) > 1 {
+			return nil, fmt.Errorf("Unexpected arguments after condition %s", datum)
+		}
+
+		return datum, nil
+	case parser.String:
+		if c.message, err = formatMessage(datum.Str, args[1:]); err != nil {
+			return nil, err
+		}
+	case parser.Symbol:
+		if !in.isConditionType(datum.Sym) {
+			return nil, fmt.Errorf("Unknown condition type %s", datum)
+		}
+
+		c.ctype = datum.Sym
+		c.message = datum.Sym
+
+		if len(args) > 1 {
+			var ctrl, ok = args[1].(parser.String)
+
+			if !ok {
+				return nil, fmt.Errorf("%w: Message of condition must be a String, not a %s (%s)",
+					ErrType,
+					args[1].Type(),
+					args[1])
+			} else if c.message, err = formatMessage(ctrl.Str, args[2:]); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%w: Condition designator must be a Condition, String or Symbol, not a %s (%s)",
+			ErrType,
+			datum.Type(),
+			datum)
+	}
+
+	return c, nil
+} // func (in *Interpreter) makeCondition(dflt string, args []parser.LispValue) (*Condition, error)
+
+// signal implements ERROR and SIGNAL. ERROR always signals its condition,
+// SIGNAL only if a HANDLER-CASE handles it, otherwise it returns NIL.
+func (in *Interpreter) signal(form string, args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err  error
+		c    *Condition
+		dflt = "SIMPLE-ERROR"
+	)
+
+	if form == "SIGNAL" {
+		dflt = "SIMPLE-CONDITION"
+	}
+
+	if c, err = in.makeCondition(dflt, args); err != nil {
+		return nil, fmt.Errorf("Invalid arguments to %s: %w",
+			form,
+			err)
+	} else if form == "ERROR" || in.isHandled(c.ctype) {
+		if in.Debug {
+			in.log.Printf("[DEBUG] %s %s\n", form, c)
+		}
+		return nil, c
+	}
+
+	return sym("nil"), nil
+} // func (in *Interpreter) signal(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// conditionAccess implements CONDITION-MESSAGE and CONDITION-TYPE.
+func conditionAccess(form string, args []parser.LispValue) (parser.LispValue, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("Wrong number of arguments to %s: %d (expected 1)",
+			form,
+			len(args))
+	}
+
+	var c, ok = args[0].(*Condition)
+
+	if !ok {
+		return nil, fmt.Errorf("%w: Argument to %s must be a Condition, not a %s",
+			ErrType,
+			form,
+			args[0].Type())
+	} else if form == "CONDITION-TYPE" {
+		return parser.MakeSymbol(c.ctype), nil
+	}
+
+	return parser.MakeString(c.message), nil
+} // func conditionAccess(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// formatMessage expands the directives ~A, ~S, ~% and ~~ in ctrl, in the
+// manner of FORMAT. ~A prints Strings without quotes, ~S prints them as
+// they would be read.
+func formatMessage(ctrl string, args []parser.LispValue) (string, error) {
+	var (
+		b    strings.Builder
+		src  = []rune(ctrl)
+		next = 0
+	)
+
+	for i := 0; i < len(src); i++ {
+		if src[i] != '~' {
+			b.WriteRune(src[i])
+			continue
+		} else if i++; i == len(src) {
+			return "", fmt.Errorf("Format string %q ends with ~", ctrl)
+		}
+
+		switch d := src[i]; d {
+		case '%':
+			b.WriteRune('\n')
+		case '~':
+			b.WriteRune('~')
+		case 'a', 'A', 's', 'S':
+			if next == len(args) {
+				return "", fmt.Errorf("Not enough arguments for format string %q", ctrl)
+			}
+
+			var arg = args[next]
+			next++
+
+			if s, ok := arg.(parser.String); ok && (d == 'a' || d == 'A') {
+				b.WriteString(s.Str)
+			} else {
+				b.WriteString(arg.String())
+			}
+		default:
+			return "", fmt.Errorf("Unsupported format directive ~%c in %q", d, ctrl)
+		}
+	}
+
+	if next < len(args) {
+		return "", fmt.Errorf("Too many arguments for format string %q: %d used, %d given",
+			ctrl,
+			next,
+			len(args))
+	}
+
+	return b.String(), nil
+} // func formatMessage(ctrl string, args []parser.LispValue) (string, error)
+
+// handlerClause is one clause of a HANDLER-CASE form.
+type handlerClause struct {
+	ctype string
+	param *parser.Symbol
+	body  *parser.ConsCell
+}
+
+// parseHandlerClause parses a clause of a HANDLER-CASE form, which has the
+// form (type ([var]) body...).
+func (in *Interpreter) parseHandlerClause(v parser.LispValue) (handlerClause, error) {
+	var (
+		ok     bool
+		hc     handlerClause
+		l      *parser.ConsCell
+		ctype  parser.Symbol
+		params []parser.LispValue
+	)
+
+	if l, ok = v.(*parser.ConsCell); !ok || l.Length() < 2 {
+		return hc, fmt.Errorf("Invalid HANDLER-CASE clause %s: expected (type ([var]) body...)",
+			v)
+	} else if ctype, ok = l.Car.(parser.Symbol); !ok {
+		return hc, fmt.Errorf("Condition type in HANDLER-CASE must be a Symbol, not a %s (%s)",
+			l.Car.Type(),
+			l.Car)
+	} else if ctype.Sym != "T" && !in.isConditionType(ctype.Sym) {
+		return hc, fmt.Errorf("Unknown condition type %s", ctype)
+	} else if params, ok = listItems(l.Rest().Car); !ok || len(params) > 1 {
+		return hc, fmt.Errorf("Invalid parameter list in HANDLER-CASE clause: %s",
+			l.Rest().Car)
+	}
+
+	hc.ctype = ctype.Sym
+	hc.body = l.Rest().Rest()
+
+	if len(params) == 1 {
+		var s parser.Symbol
+
+		if s, ok = params[0].(parser.Symbol); !ok {
+			return hc, fmt.Errorf("Parameter in HANDLER-CASE clause must be a Symbol, not a %s (%s)",
+				params[0].Type(),
+				params[0])
+		} else if err := checkParamName(s, map[string]bool{}); err != nil {
+			return hc, err
+		}
+
+		hc.param = &s
+	}
+
+	return hc, nil
+} // func (in *Interpreter) parseHandlerClause(v parser.LispValue) (handlerClause, error)
+
+// handlerCase evaluates body with handlers for the given clauses in place.
+// If evaluating body signals a condition one of the clauses matches, the
+// body of the first matching clause is evaluated with its variable bound to
+// the Condition, and its value is returned.
+func (in *Interpreter) handlerCase(body *parser.ConsCell, clauses []handlerClause) (parser.LispValue, error) {
+	var (
+		err   error
+		val   parser.LispValue
+		depth = len(in.handlers)
+	)
+
+	for _, hc := range clauses {
+		in.handlers = append(in.handlers, hc.ctype)
+	}
+
+	val, err = in.evalBody(body)
+	in.handlers = in.handlers[:depth]
+
+	if err == nil || isAbort(err) || isBlockReturn(err) {
+		return val, err
+	}
+
+	var c = AsCondition(err)
+
+	for _, hc := range clauses {
+		if !in.conditionSubtype(c.ctype, hc.ctype) {
+			continue
+		}
+
+		in.log.Printf("[TRACE] HANDLER-CASE caught %s\n", c)
+
+		in.Env.Push()
+		defer in.Env.Pop()
+
+		if hc.param != nil {
+			in.Env.Set(*hc.param, c)
+		}
+
+		return in.evalBody(hc.body)
+	}
+
+	return nil, err
+} // func (in *Interpreter) handlerCase(body *parser.ConsCell, clauses []handlerClause) (parser.LispValue, error)
+
+// isHandled returns true if a HANDLER-CASE is active that handles
+// conditions of type ctype.
+func (in *Interpreter) isHandled(ctype string) bool {
+	for _, h := range in.handlers {
+		if in.conditionSubtype(ctype, h) {
+			return true
+		}
+	}
+
+	return false
+} // func (in *Interpreter) isHandled(ctype string) bool
+
+// unwindProtect evaluates form, then the cleanup forms, no matter whether
+// evaluating form succeeded or not. It returns the result of form, unless
+// one of the cleanup forms fails.
+func (in *Interpreter) unwindProtect(form parser.LispValue, cleanup *parser.ConsCell) (parser.LispValue, error) {
+	var val, err = in.Eval(form)
+
+	if _, cerr := in.evalBody(cleanup); cerr != nil {
+		return nil, cerr
+	}
+
+	return val, err
+} // func (in *Interpreter) unwindProtect(form parser.LispValue, cleanup *parser.ConsCell) (parser.LispValue, error)
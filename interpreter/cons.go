@@ -0,0 +1,96 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/cons.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 21:14:55 krylon>
+
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// Lists are made of ConsCells, see parser.ConsCell. CAR and CDR return the
+// two halves of a ConsCell, and are defined to return NIL for NIL, the
+// empty List. RPLACA and RPLACD modify a ConsCell in place, which is
+// visible through every reference to it.
+
+// consArg checks that the argument of a List operation is a ConsCell or
+// NIL. For NIL, it returns nil.
+func consArg(form string, v parser.LispValue) (*parser.ConsCell, error) {
+	if cell, ok := v.(*parser.ConsCell); ok {
+		return cell, nil
+	} else if parser.IsNil(v) {
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("%w: Argument to %s must be a List, not a %s (%s)",
+		ErrType,
+		form,
+		v.Type(),
+		v)
+} // func consArg(form string, v parser.LispValue) (*parser.ConsCell, error)
+
+// construct implements CONS and LIST, which allocate new ConsCells.
+func (in *Interpreter) construct(form string, args []parser.LispValue) (parser.LispValue, error) {
+	if form == "LIST" {
+		return in.freshList(args)
+	} else if len(args) != 2 {
+		return nil, fmt.Errorf("Wrong number of arguments to %s: %d (expected 2)",
+			form,
+			len(args))
+	} else if err := in.alloc(1); err != nil {
+		return nil, err
+	}
+
+	return parser.Cons(args[0], args[1]), nil
+} // func (in *Interpreter) construct(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// consAccess implements CAR and CDR.
+func consAccess(form string, args []parser.LispValue) (parser.LispValue, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("Wrong number of arguments to %s: %d (expected 1)",
+			form,
+			len(args))
+	}
+
+	var cell, err = consArg(form, args[0])
+
+	if err != nil {
+		return nil, err
+	} else if cell == nil {
+		return sym("nil"), nil
+	} else if form == "CAR" {
+		return cell.Car, nil
+	}
+
+	return cell.Tail(), nil
+} // func consAccess(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// consReplace implements RPLACA and RPLACD, which return the modified
+// ConsCell.
+func consReplace(form string, args []parser.LispValue) (parser.LispValue, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("Wrong number of arguments to %s: %d (expected 2)",
+			form,
+			len(args))
+	}
+
+	var cell, ok = args[0].(*parser.ConsCell)
+
+	if !ok {
+		return nil, fmt.Errorf("%w: First argument to %s must be a ConsCell, not a %s (%s)",
+			ErrType,
+			form,
+			args[0].Type(),
+			args[0])
+	} else if form == "RPLACA" {
+		cell.Car = args[1]
+	} else {
+		cell.Cdr = args[1]
+	}
+
+	return cell, nil
+} // func consReplace(form string, args []parser.LispValue) (parser.LispValue, error)
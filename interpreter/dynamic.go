@@ -0,0 +1,115 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/dynamic.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 17:21:36 krylon>
+
+package interpreter
+
+import (
+	"github.com/blicero/krylisp/parser"
+)
+
+// Variables whose name starts and ends with an asterisk, like *DEBUG*,
+// become special variables when they are defined by DEFVAR, DEFPARAMETER or
+// VAR. Special variables are scoped dynamically rather than lexically:
+// Binding one with LET, or as a parameter of a function, changes its global
+// value for as long as the binding form is being evaluated, so every
+// function called from there sees the new value, too.
+//
+// This is implemented by shallow binding: The current value of a special
+// variable always lives in the root scope. Binding it saves the old value
+// on a stack, and Eval restores all values saved since it was called when
+// it returns, no matter whether it returns normally or with an error.
+//
+// A tail call does not return to the frame it replaces, so the value a
+// special variable had in that frame is never needed again. If the callee,
+// or a LET in tail position, binds a variable one of the replaced frames
+// has bound already, bindVar just overwrites its value instead of saving it
+// once more, so tail recursion binding a special variable runs in constant
+// space, too.
+
+// dynBinding is the value a special variable had before it was bound.
+type dynBinding struct {
+	name  *parser.Atom
+	val   parser.LispValue
+	bound bool
+}
+
+// isEarmuffed returns true if name looks like *THIS*.
+func isEarmuffed(name string) bool {
+	return len(name) > 2 && name[0] == '*' && name[len(name)-1] == '*'
+} // func isEarmuffed(name string) bool
+
+// declareSpecial makes the variable name a special variable, if its name
+// is earmuffed.
+func (in *Interpreter) declareSpecial(name parser.Symbol) {
+	if !isEarmuffed(name.Sym) || in.specials[name.Sym] {
+		return
+	} else if in.specials == nil {
+		in.specials = make(map[string]bool)
+	}
+
+	in.specials[name.Sym] = true
+	in.epoch++
+} // func (in *Interpreter) declareSpecial(name parser.Symbol)
+
+// isSpecialVar returns true if name is a special variable.
+func (in *Interpreter) isSpecialVar(name parser.Symbol) bool {
+	return in.specials[name.Sym]
+} // func (in *Interpreter) isSpecialVar(name parser.Symbol) bool
+
+// bindVar binds a variable in the current scope, or dynamically, if it is
+// a special variable.
+func (in *Interpreter) bindVar(name parser.Symbol, val parser.LispValue) {
+	if !in.isSpecialVar(name) {
+		in.Env.Set(name, val)
+		return
+	}
+
+	var (
+		key     = name.Atom()
+		root    = in.Env.root()
+		old, ok = root.bindings[key]
+	)
+
+	if !in.tail || !in.isRebound(key) {
+		in.dynamic = append(in.dynamic, dynBinding{name: key, val: old, bound: ok})
+	}
+
+	root.bindings[key] = val
+} // func (in *Interpreter) bindVar(name parser.Symbol, val parser.LispValue)
+
+// isRebound returns true if the value of the special variable name has been
+// saved since Eval was called to evaluate the tail call that is being made.
+func (in *Interpreter) isRebound(name *parser.Atom) bool {
+	for _, b := range in.dynamic[in.tailMark:] {
+		if b.name == name {
+			return true
+		}
+	}
+
+	return false
+} // func (in *Interpreter) isRebound(name *parser.Atom) bool
+
+// unwindDynamic restores the values of the special variables that were
+// bound since the stack of saved values had the height mark.
+func (in *Interpreter) unwindDynamic(mark int) {
+	if len(in.dynamic) == mark {
+		return
+	}
+
+	var root = in.Env.root()
+
+	for i := len(in.dynamic) - 1; i >= mark; i-- {
+		var b = in.dynamic[i]
+
+		if b.bound {
+			root.bindings[b.name] = b.val
+		} else {
+			delete(root.bindings, b.name)
+		}
+	}
+
+	in.dynamic = in.dynamic[:mark]
+} // func (in *Interpreter) unwindDynamic(mark int)
@@ -6,85 +6,190 @@
 
 package interpreter
 
-import "github.com/blicero/krylisp/parser"
-
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// scope holds the bindings of one level of nesting. Bindings are keyed on
+// the interned Atom of a Symbol, so the same symbol read from different
+// places in the source refers to the same binding.
+//
+// The body of a BLOCK is evaluated in a scope of its own that carries the
+// block's tag and shares the bindings of its parent.
 type scope struct {
-	bindings map[parser.Symbol]parser.LispValue
+	bindings map[*parser.Atom]parser.LispValue
 	parent   *scope
+	block    *blockTag
 }
 
-// environment is a set of bindings of symbols to values.
-type environment struct {
+// Environment is a set of bindings of symbols to values.
+type Environment struct {
 	scope *scope
 }
 
-// makeEnv creates a fresh Environment with the given parent.
-func makeEnv() *environment {
-	var env = &environment{
+// MakeEnvironment creates a fresh, empty Environment.
+func MakeEnvironment() *Environment {
+	var env = &Environment{
 		scope: &scope{
-			bindings: make(map[parser.Symbol]parser.LispValue),
+			bindings: make(map[*parser.Atom]parser.LispValue),
 		},
 	}
 
 	return env
 } // func MakeEnvironment() *Environment
 
-func (e *environment) Push() {
+// Push makes a fresh, empty scope nested in the current one the current
+// scope of the Environment. Bindings made with Set go into that scope and
+// shadow those of the enclosing scopes until the scope is removed with Pop.
+func (e *Environment) Push() {
 	var s = &scope{
-		bindings: make(map[parser.Symbol]parser.LispValue),
+		bindings: make(map[*parser.Atom]parser.LispValue),
 		parent:   e.scope,
 	}
 
 	e.scope = s
-} // func (e *environment) Push()
+} // func (e *Environment) Push()
 
-func (e *environment) Pop() {
+// Pop discards the current scope, along with its bindings, and makes its
+// parent the current scope again. It panics if the current scope is the
+// outermost one, which holds the global bindings.
+func (e *Environment) Pop() {
 	if e.scope.parent == nil {
 		panic("Cannot pop Scope, no parent!")
 	}
 
 	e.scope = e.scope.parent
-} // func (e *environment) Pop()
+} // func (e *Environment) Pop()
+
+// Enter makes a fresh scope whose parent is the given scope the current
+// scope of the environment. It returns the scope that was current before,
+// so the caller can restore it with Leave.
+// This is how closures get evaluated in the scope they were created in
+// rather than the scope they are called from.
+func (e *Environment) Enter(parent *scope) *scope {
+	var prev = e.scope
+
+	e.scope = &scope{
+		bindings: make(map[*parser.Atom]parser.LispValue),
+		parent:   parent,
+	}
+
+	return prev
+} // func (e *Environment) Enter(parent *scope) *scope
+
+// enterBlock makes a scope for the body of the BLOCK identified by tag the
+// current scope. It returns the scope that was current before, so the
+// caller can restore it with Leave.
+func (e *Environment) enterBlock(tag *blockTag) *scope {
+	var prev = e.scope
+
+	e.scope = &scope{
+		bindings: prev.bindings,
+		parent:   prev,
+		block:    tag,
+	}
+
+	return prev
+} // func (e *Environment) enterBlock(tag *blockTag) *scope
+
+// Leave makes the given scope, as returned by Enter, the current scope
+// again.
+func (e *Environment) Leave(prev *scope) {
+	e.scope = prev
+} // func (e *Environment) Leave(prev *scope)
+
+// root returns the outermost scope of the environment, where global
+// bindings live.
+func (e *Environment) root() *scope {
+	var s = e.scope
+
+	for s.parent != nil {
+		s = s.parent
+	}
+
+	return s
+} // func (e *Environment) root() *scope
 
 // Lookup attempts to look up the binding to a Symbol. If the Symbol is
 // not found in the current Environment, it recursively tries the parent
 // Environments until a binding is found or the chain of environments
 // is exhausted.
-func (e *environment) Lookup(key parser.Symbol) (parser.LispValue, bool) {
-	var (
-		ok  bool
-		val parser.LispValue
-	)
-
-	if val, ok = e.scope.bindings[key]; ok {
-		return val, ok
-	}
-
-	var s = e.scope.parent
+func (e *Environment) Lookup(key parser.Symbol) (parser.LispValue, bool) {
+	return e.scope.lookup(key.Atom())
+} // func (e *Environment) Lookup(key parser.Symbol) (parser.LispValue, error)
 
-	for s != nil {
-		if val, ok = s.bindings[key]; ok {
-			return val, ok
+// lookup finds the binding for key in s or the nearest of its ancestors.
+func (s *scope) lookup(key *parser.Atom) (parser.LispValue, bool) {
+	for ; s != nil; s = s.parent {
+		if val, ok := s.bindings[key]; ok {
+			return val, true
 		}
-
-		s = s.parent
 	}
 
 	return nil, false
-} // func (e *Environment) Lookup(key parser.Symbol) (parser.LispValue, error)
+} // func (s *scope) lookup(key *parser.Atom) (parser.LispValue, bool)
+
+// findBlock returns the tag of the innermost BLOCK named name that s is
+// nested in, or nil if there is none.
+func (s *scope) findBlock(name *parser.Atom) *blockTag {
+	for ; s != nil; s = s.parent {
+		if s.block != nil && s.block.name == name {
+			return s.block
+		}
+	}
 
-// Set sets the binding for the given Symbol to the given value. If a binding
-// for that symbol already exists, it is replaced.
-func (e *environment) Set(key parser.Symbol, val parser.LispValue) {
-	// FIXME When setting a key, I need to check first if the binding
-	//       appears in one of the parent scopes.
-	e.scope.bindings[key] = val
+	return nil
+} // func (s *scope) findBlock(name *parser.Atom) *blockTag
+
+// Set binds the given Symbol to the given value in the current scope. If a
+// binding for that symbol already exists in the current scope, it is
+// replaced. Bindings in the parent scopes are not affected, they are
+// shadowed by the new binding. To change an existing binding, use Assign.
+func (e *Environment) Set(key parser.Symbol, val parser.LispValue) {
+	e.scope.bindings[key.Atom()] = val
 } // func (e *Environment) Set(key parser.Symbol, val parser.LispValue)
 
+// Assign changes the value of the nearest existing binding of the given
+// Symbol, starting from the current scope. If the Symbol is not bound at
+// all, it returns an error wrapping ErrUnbound.
+func (e *Environment) Assign(key parser.Symbol, val parser.LispValue) error {
+	var atom = key.Atom()
+
+	for s := e.scope; s != nil; s = s.parent {
+		if _, ok := s.bindings[atom]; ok {
+			s.bindings[atom] = val
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrUnbound, key)
+} // func (e *Environment) Assign(key parser.Symbol, val parser.LispValue) error
+
+// SetGlobal sets the binding for the given Symbol in the outermost scope of
+// the environment, regardless of how deeply nested the current scope is.
+func (e *Environment) SetGlobal(key parser.Symbol, val parser.LispValue) {
+	e.root().bindings[key.Atom()] = val
+} // func (e *Environment) SetGlobal(key parser.Symbol, val parser.LispValue)
+
+// Define binds the Symbol with the given name to val in the outermost scope
+// of the Environment, i.e. it creates or replaces a global binding. The name
+// is converted to upper case, like the reader does with symbols.
+//
+// Unlike the other methods, Define takes the name as a string: It is meant
+// for Go code that provides values to Lisp under a name of its choosing,
+// like RegisterBuiltin does, and has no Symbol at hand. Code that has one,
+// e.g. because it evaluates a form, uses SetGlobal instead.
+func (e *Environment) Define(name string, val parser.LispValue) {
+	e.SetGlobal(parser.MakeSymbol(strings.ToUpper(name)), val)
+} // func (e *Environment) Define(name string, val parser.LispValue)
+
 // Delete removes the binding for the given symbol from the current scope.
 // If no binding for the symbol exists, it is a no-op.
 // If a binding for the symbol exists in the Environment's Parent(s), those are
 // not affected.
-func (e *environment) Delete(key parser.Symbol) {
-	delete(e.scope.bindings, key)
-}
+func (e *Environment) Delete(key parser.Symbol) {
+	delete(e.scope.bindings, key.Atom())
+} // func (e *Environment) Delete(key parser.Symbol)
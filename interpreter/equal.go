@@ -0,0 +1,54 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/equal.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 23:41:08 krylon>
+
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// There are three equality predicates, from strictest to loosest:
+//
+// EQ is true if both arguments are the same object. Symbols of the same
+// name are the same object. ConsCells, Functions, Builtins, Closures,
+// Macros, Conditions, HashTables and Strings are only the same object as
+// themselves, so (let ((s "a")) (eq s s)) is T, but (eq "a" "a") is NIL.
+// NIL and () are the same object.
+//
+// EQL is true for the same object, and for numbers of the same type and
+// value. (eql 1 1.0) is NIL, use = to compare numbers of different types.
+// Numbers have no identity beyond their value, so EQ agrees with EQL on
+// them: (eq 1 1) is T.
+// EQL is what CASE, MEMBER and ASSOC use.
+//
+// EQUAL is structural: Strings are EQUAL if they have the same contents,
+// ConsCells if their CARs and CDRs are EQUAL. For all other values, EQUAL
+// is EQL. The Equal method of a LispValue implements EQUAL.
+//
+// The predicates themselves live in the parser package, which needs them
+// for HashTables.
+
+// equalities maps the equality predicates to their implementations.
+var equalities = map[string]func(a, b parser.LispValue) bool{
+	"EQ":    parser.Eq,
+	"EQL":   parser.Eql,
+	"EQUAL": parser.Equal,
+}
+
+// equality implements EQ, EQL and EQUAL.
+func equality(form string, args []parser.LispValue) (parser.LispValue, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("Wrong number of arguments to %s: %d (expected 2)",
+			form,
+			len(args))
+	} else if equalities[form](args[0], args[1]) {
+		return sym("t"), nil
+	}
+
+	return sym("nil"), nil
+} // func equality(form string, args []parser.LispValue) (parser.LispValue, error)
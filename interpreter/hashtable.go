@@ -0,0 +1,233 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/hashtable.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 19. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-19 02:20:51 krylon>
+
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// HashTables map keys to values, comparing keys with EQ, EQL or EQUAL,
+// see parser.HashTable.
+//
+//	(make-hash-table [:test 'eq | 'eql | 'equal]) ; the default test is EQL
+//	(gethash key table [default])                ; => value, found-p
+//	(hash-table-contains-p key table)            ; => found-p
+//	(puthash key value table)                    ; => value
+//	(setf (gethash key table) value)             ; same as PUTHASH
+//	(remhash key table)                          ; => T if key had an entry
+//	(maphash fn table)                           ; calls (fn key value)
+//	(hash-table-count table)
+//	(hash-table-keys table)
+//
+// GETHASH returns the value for key, or default (NIL if not given) if the
+// table has no entry for key. Its second value, found-p, is T if there is
+// an entry and NIL if not, so an entry whose value is NIL can be told from
+// a missing one:
+//
+//	(multiple-value-bind (val found) (gethash key table) ...)
+//
+// HASH-TABLE-CONTAINS-P returns just found-p. MAPHASH and HASH-TABLE-KEYS
+// visit the entries in the order they were added.
+
+// hashTableArg checks that the argument of a HashTable operation is a
+// HashTable.
+func hashTableArg(form string, v parser.LispValue) (*parser.HashTable, error) {
+	var table, ok = v.(*parser.HashTable)
+
+	if !ok {
+		return nil, fmt.Errorf("%w: Argument to %s must be a HashTable, not a %s (%s)",
+			ErrType,
+			form,
+			v.Type(),
+			v)
+	}
+
+	return table, nil
+} // func hashTableArg(form string, v parser.LispValue) (*parser.HashTable, error)
+
+// makeHashTable implements MAKE-HASH-TABLE.
+func makeHashTable(form string, args []parser.LispValue) (parser.LispValue, error) {
+	var test = parser.HashEql
+
+	switch len(args) {
+	case 0:
+	case 2:
+		var (
+			kw, _   = args[0].(parser.Symbol)
+			name, _ = args[1].(parser.Symbol)
+			ok      bool
+		)
+
+		if kw.Sym != ":TEST" {
+			return nil, fmt.Errorf("Invalid keyword argument to %s: %s", form, args[0])
+		} else if test, ok = parser.HashTestNamed(name.Sym); !ok {
+			return nil, fmt.Errorf("Invalid test for %s: %s (expected EQ, EQL or EQUAL)",
+				form,
+				args[1])
+		}
+	default:
+		return nil, fmt.Errorf("Wrong number of arguments to %s: %d (expected 0 or 2)",
+			form,
+			len(args))
+	}
+
+	return parser.NewHashTable(test), nil
+} // func makeHashTable(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// hashTableContains implements HASH-TABLE-CONTAINS-P.
+func hashTableContains(form string, args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err   error
+		table *parser.HashTable
+	)
+
+	if err = arity(form, args, 2, 2); err != nil {
+		return nil, err
+	} else if table, err = hashTableArg(form, args[1]); err != nil {
+		return nil, err
+	} else if _, ok := table.Get(args[0]); ok {
+		return sym("t"), nil
+	}
+
+	return sym("nil"), nil
+} // func hashTableContains(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// putHash implements PUTHASH.
+func putHash(form string, args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err   error
+		table *parser.HashTable
+	)
+
+	if err = arity(form, args, 3, 3); err != nil {
+		return nil, err
+	} else if table, err = hashTableArg(form, args[2]); err != nil {
+		return nil, err
+	}
+
+	table.Put(args[0], args[1])
+
+	return args[1], nil
+} // func putHash(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// remHash implements REMHASH.
+func remHash(form string, args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err   error
+		table *parser.HashTable
+	)
+
+	if err = arity(form, args, 2, 2); err != nil {
+		return nil, err
+	} else if table, err = hashTableArg(form, args[1]); err != nil {
+		return nil, err
+	} else if table.Remove(args[0]) {
+		return sym("t"), nil
+	}
+
+	return sym("nil"), nil
+} // func remHash(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// hashTableCount implements HASH-TABLE-COUNT.
+func hashTableCount(form string, args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err   error
+		table *parser.HashTable
+	)
+
+	if err = arity(form, args, 1, 1); err != nil {
+		return nil, err
+	} else if table, err = hashTableArg(form, args[0]); err != nil {
+		return nil, err
+	}
+
+	return parser.Integer{Int: int64(table.Count())}, nil
+} // func hashTableCount(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// hashFunction implements the HashTable functions that return multiple
+// values, allocate new Lists or call a function: GETHASH, HASH-TABLE-KEYS
+// and MAPHASH.
+func (in *Interpreter) hashFunction(form string, args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err   error
+		table *parser.HashTable
+	)
+
+	switch form {
+	case "GETHASH":
+		if err = arity(form, args, 2, 3); err != nil {
+			return nil, err
+		} else if table, err = hashTableArg(form, args[1]); err != nil {
+			return nil, err
+		}
+
+		if val, ok := table.Get(args[0]); ok {
+			return in.multipleValues(val, sym("t")), nil
+		} else if len(args) == 3 {
+			return in.multipleValues(args[2], sym("nil")), nil
+		}
+
+		return in.multipleValues(sym("nil"), sym("nil")), nil
+	case "HASH-TABLE-KEYS":
+		if err = arity(form, args, 1, 1); err != nil {
+			return nil, err
+		} else if table, err = hashTableArg(form, args[0]); err != nil {
+			return nil, err
+		}
+
+		return in.freshList(table.Keys())
+	case "MAPHASH":
+		if err = arity(form, args, 2, 2); err != nil {
+			return nil, err
+		} else if table, err = hashTableArg(form, args[1]); err != nil {
+			return nil, err
+		}
+
+		err = table.Each(func(key, val parser.LispValue) error {
+			var _, ferr = in.funcall(args[0], []parser.LispValue{key, val})
+			return ferr
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		return sym("nil"), nil
+	default:
+		return nil, fmt.Errorf("%s is not a HashTable function", form)
+	}
+} // func (in *Interpreter) hashFunction(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// setPlace implements SETF for places other than variables. The only such
+// place is (gethash key table [default]); its subforms are evaluated
+// before the value.
+func (in *Interpreter) setPlace(place *parser.ConsCell, form parser.LispValue) (parser.LispValue, error) {
+	var (
+		err   error
+		args  []parser.LispValue
+		val   parser.LispValue
+		table *parser.HashTable
+	)
+
+	if head, _ := place.Car.(parser.Symbol); head.Sym != "GETHASH" {
+		return nil, fmt.Errorf("SETF does not support the place %s", place)
+	} else if args, err = in.evalArgs(place); err != nil {
+		return nil, err
+	} else if err = arity("GETHASH", args, 2, 3); err != nil {
+		return nil, err
+	} else if table, err = hashTableArg("GETHASH", args[1]); err != nil {
+		return nil, err
+	} else if val, err = in.Eval(form); err != nil {
+		return nil, err
+	}
+
+	table.Put(args[0], val)
+
+	return val, nil
+} // func (in *Interpreter) setPlace(place *parser.ConsCell, form parser.LispValue) (parser.LispValue, error)
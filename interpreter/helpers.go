@@ -16,50 +16,36 @@ import (
 )
 
 func list(args ...parser.LispValue) parser.LispValue {
-	fmt.Printf("Make List of %d elements\n", len(args))
-
-	if len(args) == 0 {
-		return sym("nil")
-	}
-
-	var (
-		lst = parser.List{
-			Car: args[0],
-		}
-		head, cons *parser.ConsCell
-	)
-
-	if len(args) == 1 {
-		return lst
-	}
-
-	head = new(parser.ConsCell)
-	cons = head
-
-	// for idx, val := range args[1:] {
-	// 	cons.Car = val
-	// 	if idx < len(args)-1 {
-	// 		cons.Cdr = new(parser.ConsCell)
-	// 	}
-
-	// 	cons = cons.Cdr
-	// }
-
-	for i := 1; i < len(args)-1; i++ {
-		cons.Car = args[i]
-		cons.Cdr = new(parser.ConsCell)
-		cons = cons.Cdr
+	return parser.MakeList(args...)
+} // func list(args ...parser.LispValue) parser.LispValue
+
+// listItems returns the elements of a proper list as a slice.
+// NIL counts as the empty list. If v is not a list, the second return
+// value is false.
+func listItems(v parser.LispValue) ([]parser.LispValue, bool) {
+	return parser.ListItems(v)
+} // func listItems(v parser.LispValue) ([]parser.LispValue, bool)
+
+// variableName checks that v is a Symbol that can be bound to a value, for
+// the special form form.
+func variableName(form string, v parser.LispValue) (parser.Symbol, error) {
+	var name, ok = v.(parser.Symbol)
+
+	if !ok {
+		return name, fmt.Errorf("%w: Variable in %s must be a Symbol, not a %s (%s)",
+			ErrType,
+			form,
+			v.Type(),
+			v)
+	} else if name.Sym == "T" || name.Sym == "NIL" || name.IsKeyword() {
+		return name, fmt.Errorf("%s cannot be used as a variable in %s", name, form)
 	}
 
-	cons.Car = args[len(args)-1]
-
-	lst.Cdr = head
-
-	return lst
-} // func list(args ...parser.LispValue) parser.List
+	return name, nil
+} // func variableName(form string, v parser.LispValue) (parser.Symbol, error)
 
 func sym(s string) parser.Symbol {
-	return parser.Symbol{Sym: strings.ToUpper(s)}
+	return parser.MakeSymbol(strings.ToUpper(s))
 } // func sym(s string) parser.Symbol
 
 const specialFormList = `
@@ -69,33 +55,146 @@ const specialFormList = `
 -
 /
 <
+<=
 =
 >
+>=
 and
+append
 apply
+assoc
+block
 car
+case
 cdr
 cond
+condition-message
+condition-type
 cons
 defmacro
+define-condition
+defparameter
 defun
+defvar
+dolist
+dotimes
 eq
 eql
+equal
+error
+funcall
+gensym
+gethash
+handler-case
+hash-table-contains-p
+hash-table-count
+hash-table-keys
 if
+ignore-errors
+labels
 lambda
+last
+length
 let
+let*
+letrec
 list
+loop
+macroexpand
+macroexpand-1
+make-hash-table
+mapcar
+maphash
+member
+multiple-value-bind
+multiple-value-list
 not
+nth
+nthcdr
 null
 or
+puthash
+quasiquote
 quote
+reduce
+remhash
+remove-if
+remove-if-not
+return
+return-from
+reverse
+rplaca
+rplacd
 set!
+setf
+signal
+sort
+unquote
+unquote-splicing
+unless
+unwind-protect
+values
 var
+when
 while
 `
 
 var specialForms map[string]bool
 
+// primitive implements a special form that evaluates all of its arguments
+// in order, and then only operates on their values. form is the name of the
+// special form, for error messages.
+type primitive func(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// primitives are the special forms that can be implemented as a primitive.
+// The bytecode compiler turns calls to them into a single instruction.
+var primitives = map[string]primitive{
+	"+":      numFold,
+	"*":      numFold,
+	"-":      numFold,
+	"/":      numFold,
+	"%":      numRemainder,
+	"<":      numCompareAll,
+	">":      numCompareAll,
+	"=":      numCompareAll,
+	"<=":     numCompareAll,
+	">=":     numCompareAll,
+	"NOT":    logicalNot,
+	"NULL":   logicalNot,
+	"CAR":    consAccess,
+	"CDR":    consAccess,
+	"RPLACA": consReplace,
+	"RPLACD": consReplace,
+	"NTH":    listNth,
+	"NTHCDR": listNth,
+	"LENGTH": listLength,
+	"LAST":   listLast,
+	"MEMBER": listFind,
+	"ASSOC":  listFind,
+	"EQ":     equality,
+	"EQL":    equality,
+	"EQUAL":  equality,
+
+	"MAKE-HASH-TABLE":       makeHashTable,
+	"HASH-TABLE-CONTAINS-P": hashTableContains,
+	"PUTHASH":               putHash,
+	"REMHASH":               remHash,
+	"HASH-TABLE-COUNT":      hashTableCount,
+
+	"CONDITION-MESSAGE": conditionAccess,
+	"CONDITION-TYPE":    conditionAccess,
+}
+
+// function implements a special form that evaluates all of its arguments,
+// like a primitive, but needs the Interpreter to operate on their values,
+// e.g. to allocate Lists or to call a function.
+type function func(in *Interpreter, form string, args []parser.LispValue) (parser.LispValue, error)
+
+// functions are the special forms that can be implemented as a function.
+// The map is filled in by init, since the functions end up calling Eval,
+// which refers to the map.
+var functions map[string]function
+
 func init() {
 	var symbols = common.WhiteSpace.Split(specialFormList, -1)
 
@@ -104,6 +203,34 @@ func init() {
 	for _, s := range symbols {
 		specialForms[strings.ToUpper(s)] = true
 	}
+
+	for _, name := range cxrNames() {
+		specialForms[name] = true
+		primitives[name] = cxr
+	}
+
+	functions = map[string]function{
+		"CONS":            (*Interpreter).construct,
+		"LIST":            (*Interpreter).construct,
+		"APPLY":           (*Interpreter).callFunction,
+		"FUNCALL":         (*Interpreter).callFunction,
+		"APPEND":          (*Interpreter).listFunction,
+		"REVERSE":         (*Interpreter).listFunction,
+		"MAPCAR":          (*Interpreter).listFunction,
+		"REMOVE-IF":       (*Interpreter).listFunction,
+		"REMOVE-IF-NOT":   (*Interpreter).listFunction,
+		"REDUCE":          (*Interpreter).listFunction,
+		"SORT":            (*Interpreter).listFunction,
+		"GETHASH":         (*Interpreter).hashFunction,
+		"HASH-TABLE-KEYS": (*Interpreter).hashFunction,
+		"MAPHASH":         (*Interpreter).hashFunction,
+		"VALUES":          (*Interpreter).valuesFunction,
+		"ERROR":           (*Interpreter).signal,
+		"SIGNAL":          (*Interpreter).signal,
+		"GENSYM":          (*Interpreter).gensymFunction,
+		"MACROEXPAND":     (*Interpreter).macroexpandFunction,
+		"MACROEXPAND-1":   (*Interpreter).macroexpandFunction,
+	}
 } // func init()
 
 func isSpecial(sym fmt.Stringer) bool {
@@ -112,37 +239,60 @@ func isSpecial(sym fmt.Stringer) bool {
 } // func isSpecial(sym fmt.Stringer) bool
 
 func asBool(val parser.LispValue) bool {
-	if val == nil {
-		return false
-	}
-
-	switch act := val.(type) {
-	case parser.Symbol:
-		return act.Sym != "NIL"
-	case parser.List:
-		return act.Length() != 0
-	default:
-		return true
-	}
+	return !parser.IsNil(val)
 } // func asBool(val parser.LispValue) bool
 
 // Function represents a function. I'm using a special type for these,
 // because I'll be handling those a lot, and I want to make that a bit less
 // painful.
+// env is the scope the Function was created in. When the Function is
+// called, its arguments are bound in a new scope below env, which makes
+// Functions closures. Functions with no env are evaluated in the global
+// scope.
 type Function struct {
 	name      string
 	docString string
 	argList   []parser.LispValue
+	params    *lambdaList
 	body      *parser.ConsCell
+	env       *scope
 }
 
+// lambdaList returns the parsed argument list of the Function.
+func (f *Function) lambdaList() (*lambdaList, error) {
+	if f.params == nil {
+		var err error
+
+		if f.params, err = parseLambdaList(f.argList); err != nil {
+			return nil, err
+		}
+	}
+
+	return f.params, nil
+} // func (f *Function) lambdaList() (*lambdaList, error)
+
+// displayName returns the name of the Function for use in messages.
+func (f *Function) displayName() string {
+	if f.name == "" {
+		return "anonymous function"
+	}
+
+	return f.name
+} // func (f *Function) displayName() string
+
 func (f *Function) String() string {
+	return lambdaString(f.argList, f.body)
+} // func (f *Function) String() string
+
+// lambdaString returns the printed representation of a function with the
+// given argument list and body.
+func lambdaString(argList []parser.LispValue, body *parser.ConsCell) string {
 	var (
 		sb   strings.Builder
-		args = make([]string, len(f.argList))
+		args = make([]string, len(argList))
 	)
 
-	for i, v := range f.argList {
+	for i, v := range argList {
 		args[i] = v.String()
 	}
 
@@ -150,45 +300,23 @@ func (f *Function) String() string {
 	sb.WriteString(strings.Join(args, " "))
 	sb.WriteString(")")
 
-	var c = f.body
-
-	for c != nil {
+	for c := body; c != nil; c = c.Rest() {
 		sb.WriteString("\n\t")
 		sb.WriteString(c.Car.String())
-		c = c.Cdr
 	}
 
 	sb.WriteString(")")
 
 	return sb.String()
-} // func (f *Function) String() string
+} // func lambdaString(argList []parser.LispValue, body *parser.ConsCell) string
 
 // Type returns the type of the receiver, i.e. types.Function
 func (f *Function) Type() types.Type { return types.Function }
 
 // Equal compares the receiver to another LispValue for equality.
+// Functions are only equal to themselves.
 func (f *Function) Equal(other parser.LispValue) bool {
-	var (
-		fn *Function
-		ok bool
-	)
-
-	if other == nil {
-		return f == nil
-	} else if fn, ok = other.(*Function); !ok {
-		return false
-	} else if fn == f {
-		return true
-	} else if len(f.argList) != len(fn.argList) {
-		return false
-	}
-
-	for i, s := range f.argList {
-		if !fn.argList[i].Equal(s) {
-			return false
-		}
-	}
+	var fn, ok = other.(*Function)
 
-	return f.name == fn.name &&
-		f.body.Equal(fn.body)
+	return ok && fn == f
 } // func (f *Function) Equal(other parser.LispValue) bool
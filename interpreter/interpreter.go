@@ -13,7 +13,6 @@ import (
 	"log"
 	"strings"
 
-	"github.com/blicero/krylib"
 	"github.com/blicero/krylisp/common"
 	"github.com/blicero/krylisp/logdomain"
 	"github.com/blicero/krylisp/parser"
@@ -29,15 +28,27 @@ var ErrType = errors.New("Invalid type in expression")
 
 // Interpreter implements the evaluation of Lisp expressions.
 type Interpreter struct {
-	Env           *environment
-	Debug         bool
-	GensymCounter int
-	log           *log.Logger
+	Env            *Environment
+	Debug          bool
+	GensymCounter  int
+	Limits         Limits
+	log            *log.Logger
+	handlers       []string
+	conditionTypes map[string]string
+	budget         *budget
+	profile        string
+	specials       map[string]bool
+	epoch          uint64
+	dynamic        []dynBinding
+	tail           bool
+	tailMark       int
+	values         []parser.LispValue
 }
 
 // MakeInterpreter creates a fresh Interpreter. If the given Environment is nil,
-// a fresh one is created as well.
-func MakeInterpreter(env *environment, dbg bool) (*Interpreter, error) {
+// a fresh one is created as well. Either way, the built-in functions are
+// bound in its global scope.
+func MakeInterpreter(env *Environment, dbg bool) (*Interpreter, error) {
 	var (
 		err error
 		in  = &Interpreter{
@@ -48,9 +59,11 @@ func MakeInterpreter(env *environment, dbg bool) (*Interpreter, error) {
 	if env != nil {
 		in.Env = env
 	} else {
-		in.Env = makeEnv()
+		in.Env = MakeEnvironment()
 	}
 
+	in.defineFunctions()
+
 	if in.log, err = common.GetLogger(logdomain.Interpreter); err != nil {
 		return nil, err
 	}
@@ -64,71 +77,161 @@ func MakeInterpreter(env *environment, dbg bool) (*Interpreter, error) {
 //      to a parent Environment it could have a stack of Binding maps.
 
 // Eval is the heart of the interpreter.
+//
+// Forms in tail position, e.g. the last form of a function body or the
+// branches of an IF, are not evaluated by a recursive call to Eval, but in
+// the next iteration of its loop, so tail calls do not grow the Go stack.
+// A tail call to a Function replaces the current scope with that of the
+// callee, so Eval restores the scope it was called in when it returns, as
+// well as the values of special variables bound in the meantime.
+// Special variables the replaced frames have bound already are rebound in
+// place, see bindVar.
 func (in *Interpreter) Eval(v parser.LispValue) (parser.LispValue, error) {
-	in.log.Printf("[DEBUG] Eval %T\n%s\n",
-		v,
-		spew.Sdump(v))
+	if err := in.enter(); err != nil {
+		return nil, err
+	}
 
-	switch real := v.(type) {
-	case parser.Symbol:
-		switch real.Sym {
-		case "T":
+	var (
+		entry = in.Env.scope
+		mark  = len(in.dynamic)
+	)
+
+	defer in.Env.Leave(entry)
+	defer in.unwindDynamic(mark)
+	defer in.leave()
+	defer func(tail bool, tailMark int) { in.tail, in.tailMark = tail, tailMark }(in.tail, in.tailMark)
+
+	in.tail, in.tailMark = false, mark
+
+	for {
+		var err error
+
+		if err = in.step(); err != nil {
+			return nil, err
+		}
+
+		// Secondary values only belong to the form that returned them.
+		in.values = nil
+
+		if in.Debug {
+			in.log.Printf("[DEBUG] Eval %T\n%s\n",
+				v,
+				spew.Sdump(v))
+		}
+
+		switch real := v.(type) {
+		case parser.Symbol:
+			switch real.Sym {
+			case "T":
+				return real, nil
+			case "NIL":
+				return real, nil
+			default:
+				if real.IsKeyword() {
+					return real, nil
+				}
+
+				if val, ok := in.Env.Lookup(real); ok {
+					return val, nil
+				}
+			}
+
+			return nil, fmt.Errorf("%w: %s", ErrUnbound, real)
+		case parser.Integer:
 			return real, nil
-		case "NIL":
+		case parser.Float:
 			return real, nil
-		default:
-			if real.IsKeyword() {
-				return real, nil
+		case parser.String:
+			return real, nil
+		case *Function, *Builtin, *Macro, *Closure, *parser.HashTable:
+			return real, nil
+		case *parser.ConsCell:
+			if in.Debug {
+				in.log.Printf("[DEBUG] Head of list to be evaluated is %T %s, length of List is %d\n",
+					real.Car,
+					real.Car,
+					real.Length())
 			}
 
-			if val, ok := in.Env.Lookup(real); ok {
-				return val, nil
+			// Whether the List is proper is checked by evalArgs, walking
+			// it here as well would double the cost of every call.
+			if real.Car.Type() == types.Symbol && isSpecial(real.Car) {
+				if !tailForms[real.Car.String()] {
+					return in.evalSpecial(real)
+				}
+
+				in.tail = true
+				v, err = in.reduceSpecial(real)
+				in.tail = false
+
+				if err != nil {
+					return nil, err
+				}
+
+				continue
+			} else if t := real.Car.Type(); t != types.Symbol && t != types.Function && t != types.ConsCell {
+				return nil, fmt.Errorf("Unexpected type for head of list (expected symbol): %s",
+					t)
 			}
-		}
-	case parser.Integer:
-		return real, nil
-	case parser.String:
-		return real, nil
-	case parser.List:
-		in.log.Printf("[DEBUG] Head of list to be evaluated is %T %s, length of List is %d\n",
-			real.Car,
-			real.Car,
-			real.Length())
-		if real.Car == nil && real.Cdr == nil {
-			return sym("nil"), nil
-		} else if real.Car.Type() == types.Symbol {
-			if isSpecial(real.Car) {
-				return in.evalSpecial(real)
+
+			var (
+				target parser.LispValue
+				args   []parser.LispValue
+			)
+
+			if target, err = in.callee(real); err != nil {
+				return nil, err
+			} else if m, isMacro := target.(*Macro); isMacro {
+				if v, err = in.expandMacro(m, real); err != nil {
+					return nil, err
+				}
+
+				continue
+			} else if args, err = in.evalArgs(real); err != nil {
+				return nil, err
+			} else if b, isBuiltin := target.(*Builtin); isBuiltin {
+				return b.call(args)
+			} else if cl, isClosure := target.(*Closure); isClosure {
+				return in.execute(cl, args)
 			}
 
-			in.log.Printf("[DEBUG] %s is not special.\n",
-				real.Car)
+			in.tail = true
+			v, err = in.enterFunction(target.(*Function), args)
+			in.tail = false
 
-			return in.evalList(real)
-		} else if real.Car.Type() == types.Function {
-			return in.evalList(real)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("Unsupported type %T", real)
 		}
-		return nil, fmt.Errorf("Unexpected type for head of list (expected symbol): %s",
-			real.Car.Type())
-	default:
-		return nil, fmt.Errorf("Unsupported type %t", real)
 	}
-
-	return nil, krylib.ErrNotImplemented
 } // func (in *Interpreter) Eval(v parser.LispValue) (parser.LispValue, error)
 
-func (in *Interpreter) evalSpecial(l parser.List) (parser.LispValue, error) {
-	var (
-		res parser.LispValue
-		err error
-		ok  bool
-	)
+// tailForms are the special forms that end in a tail position. Eval does
+// not pass them to evalSpecial, but to reduceSpecial.
+var tailForms = map[string]bool{
+	"IF":     true,
+	"LET":    true,
+	"LET*":   true,
+	"LETREC": true,
+	"LABELS": true,
+	"COND":   true,
+	"CASE":   true,
+	"WHEN":   true,
+	"UNLESS": true,
+	"AND":    true,
+	"OR":     true,
+
+	"MULTIPLE-VALUE-BIND": true,
+}
 
-	in.log.Printf("[DEBUG] Evaluate special form %s\n%s\n",
-		l,
-		spew.Sdump(l))
+// reduceSpecial evaluates one of the tailForms up to its tail position and
+// returns the form found there, which Eval then evaluates in place of l.
+func (in *Interpreter) reduceSpecial(l *parser.ConsCell) (parser.LispValue, error) {
+	var err error
 
-	switch form := strings.ToUpper(l.Car.String()); form {
+	switch form := l.Car.String(); form {
 	case "IF":
 		in.log.Println("[TRACE] Eval IF clause")
 		if x := l.Length(); x != 4 {
@@ -138,7 +241,7 @@ func (in *Interpreter) evalSpecial(l parser.List) (parser.LispValue, error) {
 
 		var (
 			cond, ifBranch, elseBranch parser.LispValue
-			val, branch                parser.LispValue
+			val                        parser.LispValue
 		)
 
 		cond, _ = l.At(1)
@@ -148,386 +251,513 @@ func (in *Interpreter) evalSpecial(l parser.List) (parser.LispValue, error) {
 		if val, err = in.Eval(cond); err != nil {
 			return nil, err
 		} else if asBool(val) {
-			branch = ifBranch
-		} else {
-			branch = elseBranch
+			return ifBranch, nil
 		}
 
-		return in.Eval(branch)
-	case "+":
-		var (
-			num  parser.Integer
-			cons       = l.Cdr
-			acc  int64 = 0
-		)
-
-		for cons != nil {
-			if cons.Car == nil {
-				in.log.Println("[ERROR] cons.Car is nil")
-				return nil, ErrEval
-			} else if res, err = in.Eval(cons.Car); err != nil {
-				return nil, fmt.Errorf("Error evaluating %q: %s",
-					cons.Car,
-					err.Error())
-			} else if num, ok = res.(parser.Integer); !ok {
-				return nil, fmt.Errorf("Invalid type for %q: %T (should be parser.Integer)",
-					res,
-					res)
-			}
-
-			acc += num.Int
-			cons = cons.Cdr
+		return elseBranch, nil
+	case "LET", "LET*", "LETREC", "LABELS":
+		return in.reduceLet(form, l)
+	case "COND":
+		return in.reduceCond(l)
+	case "CASE":
+		return in.reduceCase(l)
+	case "WHEN", "UNLESS":
+		var val parser.LispValue
+
+		if l.Rest() == nil {
+			return nil, fmt.Errorf("%s needs a test form", form)
+		} else if val, err = in.Eval(l.Rest().Car); err != nil {
+			return nil, err
+		} else if asBool(val) != (form == "WHEN") {
+			return sym("nil"), nil
 		}
 
-		return parser.Integer{Int: acc}, nil
-	case "*":
-		var (
-			cons       = l.Cdr
-			cnt        = 1
-			acc  int64 = 1
-		)
-
-		for cons != nil {
-			var res parser.LispValue
+		return in.reduceBody(l.Rest().Rest())
+	case "AND", "OR":
+		return in.reduceAndOr(form, l)
+	case "MULTIPLE-VALUE-BIND":
+		return in.reduceMultipleValueBind(l)
+	default:
+		return nil, fmt.Errorf("Special form %s has no tail position",
+			form)
+	}
+} // func (in *Interpreter) reduceSpecial(l *parser.ConsCell) (parser.LispValue, error)
 
-			if cons.Car == nil {
-				in.log.Println("[ERROR] cons.Car is nil")
-				return nil, ErrEval
-			} else if res, err = in.Eval(cons.Car); err != nil {
-				return nil, fmt.Errorf("Error evaluating argument #%d (%s): %s",
-					cnt,
-					cons.Car,
-					err.Error())
-			} else if res.Type() != types.Integer {
-				return nil, fmt.Errorf("Unexpected type: %s (expect Integer)",
-					res.Type())
-			}
+func (in *Interpreter) evalSpecial(l *parser.ConsCell) (parser.LispValue, error) {
+	var (
+		err error
+		ok  bool
+	)
 
-			acc *= res.(parser.Integer).Int
-			cons = cons.Cdr
-		}
+	if in.Debug {
+		in.log.Printf("[DEBUG] Evaluate special form %s\n%s\n",
+			l,
+			spew.Sdump(l))
+	}
 
-		return parser.Integer{Int: acc}, nil
-	case "<":
-		var (
-			res  parser.LispValue
-			n    parser.Integer
-			cons = l.Cdr
-		)
+	var form = strings.ToUpper(l.Car.String())
 
-		in.log.Printf("[TRACE] Evaluate less-than (<): %s\n",
-			spew.Sdump(l.Cdr))
+	if prim, isPrim := primitives[form]; isPrim {
+		var args []parser.LispValue
 
-		if res, err = in.Eval(cons.Cdr.Car); err != nil {
-			var msg = fmt.Sprintf("Error evaluating %q: %s",
-				cons.Cdr.Car,
-				err.Error())
-			in.log.Printf("[ERROR] %s\n", msg)
-			return nil, errors.New(msg)
-		} else if n, ok = res.(parser.Integer); !ok {
-			return nil, fmt.Errorf("Invalid type for <: %s (expected Integer)",
-				cons.Car.Type())
+		if args, err = in.evalArgs(l); err != nil {
+			return nil, err
 		}
 
-		cons = cons.Cdr
+		return prim(form, args)
+	} else if fn, isFunc := functions[form]; isFunc {
+		var args []parser.LispValue
 
-		for cons != nil {
-			var num parser.Integer
-			if res, err = in.Eval(cons.Car); err != nil {
-				var msg = fmt.Sprintf("Error evaluating %q: %s",
-					cons.Cdr.Car,
-					err.Error())
-				in.log.Printf("[ERROR] %s\n", msg)
-				return nil, errors.New(msg)
-			} else if num, ok = res.(parser.Integer); !ok {
-				return nil, fmt.Errorf("Unexpected type for <: %s (expected Integer)",
-					cons.Car.Type())
-			}
-
-			if num.Int < n.Int {
-				return sym("nil"), nil
-			}
-
-			cons = cons.Cdr
+		if args, err = in.evalArgs(l); err != nil {
+			return nil, err
 		}
 
-		return sym("t"), nil
-	case "NULL":
-		if cnt := l.Length(); cnt != 2 {
-			return nil, fmt.Errorf("Wrong number of arguments for NULL: %d (expect 0)",
+		return fn(in, form, args)
+	}
+
+	switch form {
+	case "DEFUN":
+		if cnt := l.Length(); cnt < 3 {
+			return nil, fmt.Errorf("Wrong number of arguments to DEFUN: %d (expect >= 3)",
 				cnt)
 		}
 
-		var arg = l.Cdr.Car
+		var (
+			name parser.Symbol
+			fn   *Function
+		)
 
-		if asBool(arg) {
-			return sym("nil"), nil
+		if name, ok = l.Rest().Car.(parser.Symbol); !ok {
+			return nil, fmt.Errorf("First argument to DEFUN must be a symbol, not a %s",
+				l.Rest().Car.Type())
+		} else if fn, err = in.makeFunction(name.Sym, l.Rest().Rest().Car, l.Rest().Rest().Rest()); err != nil {
+			return nil, fmt.Errorf("Invalid definition of function %s: %w",
+				name,
+				err)
 		}
 
-		return sym("t"), nil
-	case "DEFUN":
+		in.Env.SetGlobal(name, fn)
+
+		return name, nil
+	case "DEFMACRO":
 		if cnt := l.Length(); cnt < 3 {
-			return nil, fmt.Errorf("Wrong number of arguments to DEFUN: %d (expect >= 3)",
+			return nil, fmt.Errorf("Wrong number of arguments to DEFMACRO: %d (expect >= 3)",
 				cnt)
 		}
 
 		var (
-			v, name   parser.LispValue
-			argList   parser.List
-			args      []parser.LispValue
-			docString string
-			body      *parser.ConsCell
+			name parser.Symbol
+			fn   *Function
 		)
 
-		if argList, ok = l.Cdr.Cdr.Car.(parser.List); !ok {
-			return nil, fmt.Errorf("Second argument to defun must be a List of arguments, not a %T",
-				l.Cdr.Cdr.Car)
+		if name, ok = l.Rest().Car.(parser.Symbol); !ok {
+			return nil, fmt.Errorf("First argument to DEFMACRO must be a symbol, not a %s",
+				l.Rest().Car.Type())
+		} else if fn, err = in.makeFunction(name.Sym, l.Rest().Rest().Car, l.Rest().Rest().Rest()); err != nil {
+			return nil, fmt.Errorf("Invalid definition of macro %s: %w",
+				name,
+				err)
 		}
 
-		v, _ = l.At(2)
+		in.Env.SetGlobal(name, &Macro{expander: fn})
+		in.epoch++
 
-		if v.Type() == types.String {
-			docString = v.(parser.String).Str
-			body = l.Cdr.Cdr.Cdr
-		} else {
-			body = l.Cdr.Cdr
+		return name, nil
+	case "SET!", "SETF":
+		if cnt := l.Length(); cnt != 3 {
+			return nil, fmt.Errorf("Wrong number of arguments to %s: %d (expected 2)",
+				form,
+				cnt-1)
+		} else if place, isPlace := l.Rest().Car.(*parser.ConsCell); isPlace && form == "SETF" {
+			return in.setPlace(place, l.Rest().Rest().Car)
 		}
 
-		name = l.Cdr.Car
+		var (
+			name parser.Symbol
+			val  parser.LispValue
+		)
 
-		if t := name.Type(); t != types.Symbol {
-			return nil, fmt.Errorf("First argument to DEFUN must be a symbol, not a %s",
-				t)
+		if name, err = variableName(form, l.Rest().Car); err != nil {
+			return nil, err
+		} else if val, err = in.Eval(l.Rest().Rest().Car); err != nil {
+			return nil, err
+		} else if err = in.Env.Assign(name, val); err != nil {
+			return nil, err
 		}
 
-		args = make([]parser.LispValue, 0, 4)
-
-		if argList.Car != nil {
-			args = append(args, argList.Car)
-			var a = argList.Cdr
+		return val, nil
+	case "DEFVAR", "DEFPARAMETER", "VAR":
+		var (
+			cnt  = l.Length()
+			name parser.Symbol
+			val  parser.LispValue = sym("nil")
+		)
 
-			for a != nil {
-				args = append(args, a.Car)
-				a = a.Cdr
+		if cnt < 2 || cnt > 4 {
+			return nil, fmt.Errorf("Wrong number of arguments to %s: %d (expected 1 to 3)",
+				form,
+				cnt-1)
+		} else if name, err = variableName(form, l.Rest().Car); err != nil {
+			return nil, err
+		} else if cnt == 4 {
+			if _, ok = l.Rest().Rest().Rest().Car.(parser.String); !ok {
+				return nil, fmt.Errorf("Documentation of %s must be a String, not a %s",
+					name,
+					l.Rest().Rest().Rest().Car.Type())
 			}
 		}
 
-		var fn = &Function{
-			name:      name.(parser.Symbol).Sym,
-			docString: docString,
-			argList:   args,
-			body:      body,
+		in.declareSpecial(name)
+
+		// DEFVAR does not change a variable that is already bound, and
+		// it does not even evaluate the initial value in that case.
+		if _, ok = in.Env.root().lookup(name.Atom()); ok && form == "DEFVAR" {
+			return name, nil
+		} else if cnt > 2 {
+			if val, err = in.Eval(l.Rest().Rest().Car); err != nil {
+				return nil, err
+			}
 		}
 
-		in.Env.Set(name.(parser.Symbol), fn)
+		in.Env.SetGlobal(name, val)
 
 		return name, nil
-	case "CONS":
-		if cnt := l.Length(); cnt != 3 {
-			return nil, fmt.Errorf("Wrong number of arguments to CONS: %d (expected 2)",
+	case "LAMBDA":
+		if cnt := l.Length(); cnt < 2 {
+			return nil, fmt.Errorf("Wrong number of arguments to LAMBDA: %d (expect >= 1)",
 				cnt-1)
 		}
 
-		var (
-			v1, v2 parser.LispValue
-			cell   *parser.ConsCell
-		)
+		return in.makeFunction("", l.Rest().Car, l.Rest().Rest())
+	case "MULTIPLE-VALUE-LIST":
+		return in.multipleValueList(l)
+	case "QUOTE":
+		if cnt := l.Length(); cnt != 2 {
+			return nil, fmt.Errorf("Wrong number of arguments to QUOTE: %d (expected 1)",
+				cnt-1)
+		}
 
-		if v1, err = in.Eval(l.Cdr.Car); err != nil {
-			return nil, fmt.Errorf("Error evaluating %q: %s",
-				l.Cdr.Car,
-				err.Error())
-		} else if v2, err = in.Eval(l.Cdr.Cdr.Car); err != nil {
-			return nil, fmt.Errorf("Error evaluating %q: %s",
-				l.Cdr.Cdr.Car,
-				err.Error())
+		return l.Rest().Car, nil
+	case "QUASIQUOTE":
+		if cnt := l.Length(); cnt != 2 {
+			return nil, fmt.Errorf("Wrong number of arguments to QUASIQUOTE: %d (expected 1)",
+				cnt-1)
 		}
 
-		if cell, ok = v2.(*parser.ConsCell); ok {
-			return &parser.ConsCell{Car: v1, Cdr: cell}, nil
+		return in.quasiquote(l.Rest().Car, 1)
+	case "UNQUOTE", "UNQUOTE-SPLICING":
+		return nil, fmt.Errorf("%s is not allowed outside of QUASIQUOTE: %s",
+			form,
+			l)
+	case "HANDLER-CASE":
+		if cnt := l.Length(); cnt < 2 {
+			return nil, fmt.Errorf("Wrong number of arguments to HANDLER-CASE: %d (expect >= 1)",
+				cnt-1)
 		}
 
-		return &parser.ConsCell{Car: v1, Cdr: &parser.ConsCell{Car: v2}}, nil
-	case "LIST":
-		var (
-			cons = l.Cdr.Cdr.Cdr
-			lst  parser.List
-			tail *parser.ConsCell
-		)
+		var clauses []handlerClause
+
+		for c := l.Rest().Rest(); c != nil; c = c.Rest() {
+			var hc handlerClause
+
+			if hc, err = in.parseHandlerClause(c.Car); err != nil {
+				return nil, err
+			}
 
-		if lst.Car, err = in.Eval(l.Cdr.Car); err != nil {
-			return nil, fmt.Errorf("Error evaluating list element %s: %s",
-				l.Cdr.Car,
-				err.Error())
+			clauses = append(clauses, hc)
 		}
 
-		lst.Cdr = new(parser.ConsCell)
-		tail = lst.Cdr
+		return in.handlerCase(&parser.ConsCell{Car: l.Rest().Car}, clauses)
+	case "BLOCK":
+		var name parser.Symbol
 
-		for cons != nil {
-			if tail.Car, err = in.Eval(cons.Car); err != nil {
-				return nil, fmt.Errorf("Error evaluating list element %s: %s",
-					cons.Car,
-					err.Error())
-			}
+		if l.Rest() == nil {
+			return nil, fmt.Errorf("BLOCK needs a name")
+		} else if name, ok = l.Rest().Car.(parser.Symbol); !ok {
+			return nil, fmt.Errorf("Name of BLOCK must be a Symbol, not a %s (%s)",
+				l.Rest().Car.Type(),
+				l.Rest().Car)
+		}
 
-			tail.Cdr = new(parser.ConsCell)
-			cons = cons.Cdr
+		return in.block(name, func() (parser.LispValue, error) {
+			return in.evalBody(l.Rest().Rest())
+		})
+	case "RETURN-FROM":
+		var name parser.Symbol
+
+		if cnt := l.Length(); cnt < 2 || cnt > 3 {
+			return nil, fmt.Errorf("Wrong number of arguments to RETURN-FROM: %d (expected 1 or 2)",
+				cnt-1)
+		} else if name, ok = l.Rest().Car.(parser.Symbol); !ok {
+			return nil, fmt.Errorf("Name of BLOCK must be a Symbol, not a %s (%s)",
+				l.Rest().Car.Type(),
+				l.Rest().Car)
+		} else if l.Rest().Rest() == nil {
+			return nil, in.returnFrom(name, sym("nil"))
 		}
 
-		return lst, nil
-	case "APPLY":
-		var (
-			fn  *Function
-			val parser.LispValue
-		)
+		return nil, in.returnFrom(name, l.Rest().Rest().Car)
+	case "RETURN":
+		if cnt := l.Length(); cnt > 2 {
+			return nil, fmt.Errorf("Wrong number of arguments to RETURN: %d (expected 0 or 1)",
+				cnt-1)
+		} else if l.Rest() == nil {
+			return nil, in.returnFrom(sym("nil"), sym("nil"))
+		}
 
-		switch v := l.Cdr.Car.(type) {
-		case *Function:
-			fn = v
-		case parser.Symbol:
-			if val, ok = in.Env.Lookup(v); !ok {
-				return nil, fmt.Errorf("Cannot apply unbound symbol %s",
-					v)
-			} else if val.Type() == types.Function {
-				fn = val.(*Function)
-			} else {
-				return nil, fmt.Errorf("Value of %s is not a function (%s)",
-					v,
-					val.Type())
+		return nil, in.returnFrom(sym("nil"), l.Rest().Car)
+	case "WHILE", "DOTIMES", "DOLIST", "LOOP":
+		return in.block(sym("nil"), func() (parser.LispValue, error) {
+			switch form {
+			case "WHILE":
+				return in.while(l)
+			case "LOOP":
+				return in.loop(l)
+			default:
+				return in.doLoop(form, l)
 			}
-		default:
-			return nil, fmt.Errorf("Cannot apply a %s (%s)",
-				v.Type(),
-				v)
+		})
+	case "IGNORE-ERRORS":
+		return in.handlerCase(l.Rest(), []handlerClause{{ctype: "ERROR"}})
+	case "UNWIND-PROTECT":
+		if cnt := l.Length(); cnt < 2 {
+			return nil, fmt.Errorf("Wrong number of arguments to UNWIND-PROTECT: %d (expect >= 1)",
+				cnt-1)
 		}
 
-		if val, err = in.Eval(l.Cdr.Cdr); err != nil {
-			return nil, fmt.Errorf("Errort evaluating %q: %s",
-				l.Cdr.Cdr,
-				l.Cdr.Cdr.Type())
+		return in.unwindProtect(l.Rest().Car, l.Rest().Rest())
+	case "DEFINE-CONDITION":
+		if cnt := l.Length(); cnt != 3 {
+			return nil, fmt.Errorf("Wrong number of arguments to DEFINE-CONDITION: %d (expected 2)",
+				cnt-1)
 		}
 
-		var fncall = parser.List{
-			Car: fn,
-			Cdr: &parser.ConsCell{Car: val},
-		}
+		var (
+			name, parent parser.Symbol
+			supers       []parser.LispValue
+		)
 
-		return in.Eval(fncall)
-	case "CAR":
-		switch v := l.Cdr.Car.(type) {
-		case parser.List:
-			return v.Car, nil
-		case parser.ConsCell:
-			return v.Car, nil
-		default:
-			return nil, fmt.Errorf("First argument to CAR must be a List or ConsCell, not a %T",
-				l.Cdr.Car)
+		if name, ok = l.Rest().Car.(parser.Symbol); !ok {
+			return nil, fmt.Errorf("First argument to DEFINE-CONDITION must be a symbol, not a %s",
+				l.Rest().Car.Type())
+		} else if supers, ok = listItems(l.Rest().Rest().Car); !ok || len(supers) != 1 {
+			return nil, fmt.Errorf("DEFINE-CONDITION expects a list of exactly one supertype, not %s",
+				l.Rest().Rest().Car)
+		} else if parent, ok = supers[0].(parser.Symbol); !ok {
+			return nil, fmt.Errorf("Supertype of condition must be a symbol, not a %s",
+				supers[0].Type())
+		} else if err = in.defineCondition(name.Sym, parent.Sym); err != nil {
+			return nil, err
 		}
+
+		return name, nil
 	default:
 		var msg = fmt.Sprintf("Special form %s is not implemented, yet",
 			form)
 		in.log.Printf("[ERROR] %s\n", msg)
 		return nil, errors.New(msg)
 	}
-} // func (in *Interpreter) evalSpecial(l parser.List) (parser.LispValue, error)
+} // func (in *Interpreter) evalSpecial(l *parser.ConsCell) (parser.LispValue, error)
 
-func (in *Interpreter) evalList(l parser.List) (parser.LispValue, error) {
+// evalArgs evaluates the arguments of a form, i.e. all elements of the list
+// except for the first, and returns the results in order. It fails if the
+// list is improper.
+func (in *Interpreter) evalArgs(l *parser.ConsCell) ([]parser.LispValue, error) {
 	var (
-		ok        bool
-		head, val parser.LispValue
-		fn        *Function
-		cnt       int
+		err  error
+		res  parser.LispValue
+		last = l
+		args = make([]parser.LispValue, 0, 4)
 	)
 
-	if cnt = l.Length(); cnt < 1 {
-		return sym("nil"), nil
+	for cons := l.Rest(); cons != nil; cons = cons.Rest() {
+		last = cons
+
+		if cons.Car == nil {
+			in.log.Println("[ERROR] cons.Car is nil")
+			return nil, ErrEval
+		} else if res, err = in.Eval(cons.Car); err != nil {
+			return nil, fmt.Errorf("Error evaluating %q: %w",
+				cons.Car,
+				err)
+		}
+
+		args = append(args, res)
+	}
+
+	if !parser.IsNil(last.Cdr) {
+		return nil, fmt.Errorf("Cannot evaluate improper list %s", l)
 	}
 
-	head = l.Car
+	in.values = nil
+
+	return args, nil
+} // func (in *Interpreter) evalArgs(l *parser.ConsCell) ([]parser.LispValue, error)
+
+// callee resolves the head of a function call to the Function, Builtin or
+// Macro it refers to.
+func (in *Interpreter) callee(l *parser.ConsCell) (parser.LispValue, error) {
+	var (
+		err error
+		val parser.LispValue
+	)
 
-	switch v := head.(type) {
+	switch v := l.Car.(type) {
 	case parser.Symbol:
+		var ok bool
+
 		if val, ok = in.Env.Lookup(v); !ok {
-			return nil, fmt.Errorf("No binding was found for %s",
-				v)
-		} else if fn, ok = val.(*Function); !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUndefined, v)
+		}
+
+		switch val.(type) {
+		case *Function, *Builtin, *Macro, *Closure:
+			if in.Debug {
+				in.log.Printf("[TRACE] Evaluating call to %s\n",
+					v)
+			}
+
+			return val, nil
+		default:
 			return nil, fmt.Errorf("Type error: Binding for %s is not a function, but a %s (%s)",
 				v,
 				val.Type(),
 				val)
 		}
+	case *Function, *Builtin, *Closure:
+		return v, nil
+	case *parser.ConsCell:
+		if val, err = in.Eval(v); err != nil {
+			return nil, err
+		}
+
+		switch val.(type) {
+		case *Function, *Builtin, *Closure:
+			return val, nil
+		}
 
-		in.log.Printf("[TRACE] Evaluating call to %s\n",
-			fn.name)
-	case *Function:
-		fn = v
+		return nil, fmt.Errorf("Type error: Head of list %s evaluated to a %s (%s), not a function",
+			v,
+			val.Type(),
+			val)
 	default:
 		return nil, fmt.Errorf("Head of list must be a Symbol that resolves to a function or a Function object, not a %T", v)
 	}
-
-	// Next, we need to evaluate the arguments to the function call, bind
-	// them to the argument list of the function, push those to the
-	// Environment stack, and evaluate the function body.
-
-	if cnt != len(fn.argList)+1 {
-		return nil, fmt.Errorf("Incorrect number of arguments in function call: want %d, got %d",
-			cnt-1,
-			len(fn.argList))
-	}
-
+} // func (in *Interpreter) callee(l *parser.ConsCell) (parser.LispValue, error)
+
+// enterFunction prepares the evaluation of a call to fn: It makes a fresh
+// scope below the one fn was created in the current one, binds the
+// arguments there, and evaluates all forms of the body except the last one.
+// The last form is returned, for the caller to evaluate in tail position.
+// The scope that was current before is not restored, that is up to the
+// caller.
+func (in *Interpreter) enterFunction(fn *Function, args []parser.LispValue) (parser.LispValue, error) {
 	var (
-		cell = l.Cdr
-		args = make([]parser.LispValue, 0, len(fn.argList))
+		err  error
+		ll   *lambdaList
+		env  = fn.env
+		body = fn.body
 	)
 
-	for cell != nil {
-		var (
-			err error
-			res parser.LispValue
-		)
-
-		in.log.Printf("[TRACE] Evaluate argument: %s\n",
-			cell.Car)
+	if ll, err = fn.lambdaList(); err != nil {
+		return nil, fmt.Errorf("Invalid argument list of %s: %w",
+			fn.displayName(),
+			err)
+	} else if env == nil {
+		env = in.Env.root()
+	}
 
-		if res, err = in.Eval(cell.Car); err != nil {
-			in.log.Printf("[ERROR] Error evaluating %q: %s\n",
-				cell.Car,
-				err.Error())
-			return nil, err
-		}
+	in.Env.Enter(env)
 
-		args = append(args, res)
-		cell = cell.Cdr
+	if err = in.bindArgs(fn, ll, args); err != nil {
+		return nil, err
 	}
 
-	in.Env.Push()
+	return in.reduceBody(body)
+} // func (in *Interpreter) enterFunction(fn *Function, args []parser.LispValue) (parser.LispValue, error)
 
-	for i, s := range fn.argList {
-		in.Env.Set(s.(parser.Symbol), args[i])
-	}
+// apply calls the Function fn with the given, already evaluated, arguments.
+// The arguments are bound in a fresh scope whose parent is the scope the
+// Function was created in, and the body is evaluated in that scope.
+func (in *Interpreter) apply(fn *Function, args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err  error
+		tail parser.LispValue
+		prev = in.Env.scope
+		mark = len(in.dynamic)
+	)
 
-	defer in.Env.Pop()
+	defer in.Env.Leave(prev)
+	defer in.unwindDynamic(mark)
+
+	if tail, err = in.enterFunction(fn, args); err != nil {
+		return nil, err
+	}
 
-	in.log.Printf("[TRACE] Evaluate function body:\n%s\n",
-		spew.Sdump(fn.body))
+	return in.Eval(tail)
+} // func (in *Interpreter) apply(fn *Function, args []parser.LispValue) (parser.LispValue, error)
 
+// evalBody evaluates a sequence of forms, as in a function body, and
+// returns the value of the last one. An empty body evaluates to NIL.
+func (in *Interpreter) evalBody(body *parser.ConsCell) (parser.LispValue, error) {
 	var (
-		err  error
-		res  parser.LispValue
-		body = fn.body
+		err error
+		res parser.LispValue = sym("nil")
 	)
 
-	for body != nil {
+	for ; body != nil; body = body.Rest() {
 		if res, err = in.Eval(body.Car); err != nil {
-			in.log.Printf("[ERROR] Error evaluating expression %s: %s\n",
-				body.Car,
-				err.Error())
+			if !isBlockReturn(err) {
+				in.log.Printf("[ERROR] Error evaluating expression %s: %s\n",
+					body.Car,
+					err.Error())
+			}
 			return nil, err
 		}
-
-		body = body.Cdr
 	}
 
 	return res, nil
-} // func (in *Interpreter) evalList(l parser.List) (parser.LispValue, error)
+} // func (in *Interpreter) evalBody(body *parser.ConsCell) (parser.LispValue, error)
+
+// makeFunction creates a Function from the argument list and body of a
+// DEFUN or LAMBDA form. If the body starts with a string and contains more
+// forms after it, that string is the Function's documentation.
+// The Function captures the current scope, so it can refer to the
+// bindings visible where it was defined.
+func (in *Interpreter) makeFunction(name string, argList parser.LispValue, body *parser.ConsCell) (*Function, error) {
+	var (
+		err       error
+		ok        bool
+		args      []parser.LispValue
+		params    *lambdaList
+		docString string
+	)
+
+	if args, ok = listItems(argList); !ok {
+		return nil, fmt.Errorf("Argument list must be a List, not a %s (%s)",
+			argList.Type(),
+			argList)
+	} else if params, err = parseLambdaList(args); err != nil {
+		return nil, fmt.Errorf("Invalid argument list %s: %w",
+			argList,
+			err)
+	}
+
+	if body != nil && body.Rest() != nil {
+		if doc, isStr := body.Car.(parser.String); isStr {
+			docString = doc.Str
+			body = body.Rest()
+		}
+	}
+
+	var fn = &Function{
+		name:      name,
+		docString: docString,
+		argList:   args,
+		params:    params,
+		body:      body,
+		env:       in.Env.scope,
+	}
+
+	return fn, nil
+} // func (in *Interpreter) makeFunction(name string, argList parser.LispValue, body *parser.ConsCell) (*Function, error)
@@ -0,0 +1,365 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/lambdalist.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 17. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-17 22:31:07 krylon>
+
+package interpreter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// A lambda list may consist of up to four sections, in this order:
+//
+//	(required... &optional opt... &rest rest &key key... &allow-other-keys)
+//
+// &body is accepted as a synonym for &rest, as it reads better in macros.
+// Optional and keyword parameters can be given either as a plain Symbol or
+// as a list (name default supplied-p), where default is a form that is
+// evaluated if no argument was passed for the parameter, and supplied-p is
+// bound to T or NIL, depending on whether an argument was passed.
+
+// param is an optional or keyword parameter.
+type param struct {
+	name     parser.Symbol
+	keyword  parser.Symbol
+	init     parser.LispValue
+	supplied *parser.Symbol
+}
+
+// lambdaList is the parsed form of the argument list of a Function.
+type lambdaList struct {
+	required       []parser.Symbol
+	optional       []param
+	rest           *parser.Symbol
+	key            []param
+	hasKey         bool
+	allowOtherKeys bool
+}
+
+// minArgs returns the minimum number of arguments a call must pass.
+func (ll *lambdaList) minArgs() int {
+	return len(ll.required)
+} // func (ll *lambdaList) minArgs() int
+
+// maxArgs returns the maximum number of arguments a call may pass, or -1
+// if there is no upper limit.
+func (ll *lambdaList) maxArgs() int {
+	if ll.rest != nil || ll.hasKey {
+		return -1
+	}
+
+	return len(ll.required) + len(ll.optional)
+} // func (ll *lambdaList) maxArgs() int
+
+// arity describes the number of arguments accepted, for error messages.
+func (ll *lambdaList) arity() string {
+	var lo, hi = ll.minArgs(), ll.maxArgs()
+
+	switch {
+	case hi == -1:
+		return fmt.Sprintf("at least %d", lo)
+	case lo == hi:
+		return fmt.Sprintf("exactly %d", lo)
+	default:
+		return fmt.Sprintf("between %d and %d", lo, hi)
+	}
+} // func (ll *lambdaList) arity() string
+
+// parseLambdaList parses the argument list of a DEFUN or LAMBDA form.
+func parseLambdaList(args []parser.LispValue) (*lambdaList, error) {
+	const (
+		secRequired = iota
+		secOptional
+		secRest
+		secKey
+		secAllowOtherKeys
+	)
+
+	var (
+		ll      = new(lambdaList)
+		section = secRequired
+		seen    = make(map[string]bool)
+	)
+
+	for _, a := range args {
+		var s, isSym = a.(parser.Symbol)
+
+		if isSym && strings.HasPrefix(s.Sym, "&") {
+			var next int
+
+			switch s.Sym {
+			case "&OPTIONAL":
+				next = secOptional
+			case "&REST", "&BODY":
+				next = secRest
+			case "&KEY":
+				next = secKey
+				ll.hasKey = true
+			case "&ALLOW-OTHER-KEYS":
+				if section != secKey {
+					return nil, fmt.Errorf("&ALLOW-OTHER-KEYS must follow the &KEY parameters")
+				}
+				next = secAllowOtherKeys
+				ll.allowOtherKeys = true
+			default:
+				return nil, fmt.Errorf("Unknown lambda list keyword %s", s)
+			}
+
+			if next <= section {
+				return nil, fmt.Errorf("Lambda list keyword %s is misplaced", s)
+			} else if section == secRest && ll.rest == nil {
+				return nil, fmt.Errorf("&REST must be followed by a Symbol")
+			}
+
+			section = next
+			continue
+		}
+
+		var (
+			err error
+			p   param
+		)
+
+		switch section {
+		case secRequired:
+			if !isSym {
+				return nil, fmt.Errorf("Required parameter must be a Symbol, not a %s (%s)",
+					a.Type(),
+					a)
+			}
+			p.name = s
+		case secOptional, secKey:
+			if p, err = parseParam(a); err != nil {
+				return nil, err
+			}
+		case secRest:
+			if !isSym {
+				return nil, fmt.Errorf("&REST parameter must be a Symbol, not a %s (%s)",
+					a.Type(),
+					a)
+			} else if ll.rest != nil {
+				return nil, fmt.Errorf("&REST takes exactly one parameter, found %s after %s",
+					s,
+					ll.rest)
+			}
+			p.name = s
+		case secAllowOtherKeys:
+			return nil, fmt.Errorf("Unexpected parameter %s after &ALLOW-OTHER-KEYS", a)
+		}
+
+		if err = checkParamName(p.name, seen); err != nil {
+			return nil, err
+		} else if p.supplied != nil {
+			if err = checkParamName(*p.supplied, seen); err != nil {
+				return nil, err
+			}
+		}
+
+		switch section {
+		case secRequired:
+			ll.required = append(ll.required, p.name)
+		case secOptional:
+			ll.optional = append(ll.optional, p)
+		case secRest:
+			ll.rest = &p.name
+		case secKey:
+			p.keyword = parser.MakeSymbol(":" + p.name.Sym)
+			ll.key = append(ll.key, p)
+		}
+	}
+
+	if section == secRest && ll.rest == nil {
+		return nil, fmt.Errorf("&REST must be followed by a Symbol")
+	}
+
+	return ll, nil
+} // func parseLambdaList(args []parser.LispValue) (*lambdaList, error)
+
+// parseParam parses the specification of an &optional or &key parameter,
+// i.e. either name or (name [default [supplied-p]]).
+func parseParam(v parser.LispValue) (param, error) {
+	var (
+		p     param
+		ok    bool
+		items []parser.LispValue
+	)
+
+	if p.name, ok = v.(parser.Symbol); ok {
+		return p, nil
+	} else if items, ok = listItems(v); !ok || len(items) == 0 || len(items) > 3 {
+		return p, fmt.Errorf("Invalid parameter specification %s: expected name or (name [default [supplied-p]])",
+			v)
+	} else if p.name, ok = items[0].(parser.Symbol); !ok {
+		return p, fmt.Errorf("Parameter name must be a Symbol, not a %s (%s)",
+			items[0].Type(),
+			items[0])
+	}
+
+	if len(items) > 1 {
+		p.init = items[1]
+	}
+
+	if len(items) == 3 {
+		var s parser.Symbol
+
+		if s, ok = items[2].(parser.Symbol); !ok {
+			return p, fmt.Errorf("Supplied-p parameter must be a Symbol, not a %s (%s)",
+				items[2].Type(),
+				items[2])
+		}
+
+		p.supplied = &s
+	}
+
+	return p, nil
+} // func parseParam(v parser.LispValue) (param, error)
+
+// checkParamName makes sure a Symbol can be used as a parameter and does
+// not appear twice in the same lambda list.
+func checkParamName(s parser.Symbol, seen map[string]bool) error {
+	if s.Sym == "T" || s.Sym == "NIL" || s.IsKeyword() {
+		return fmt.Errorf("%s cannot be used as a parameter", s)
+	} else if seen[s.Sym] {
+		return fmt.Errorf("Parameter %s appears more than once", s)
+	}
+
+	seen[s.Sym] = true
+	return nil
+} // func checkParamName(s parser.Symbol, seen map[string]bool) error
+
+// bindArgs binds the arguments of a call to the parameters of fn in the
+// current scope. Default forms are evaluated in that scope as well, so they
+// can refer to the parameters to their left.
+func (in *Interpreter) bindArgs(fn *Function, ll *lambdaList, args []parser.LispValue) error {
+	if n := len(args); n < ll.minArgs() || (ll.maxArgs() != -1 && n > ll.maxArgs()) {
+		return fmt.Errorf("Incorrect number of arguments in call to %s: want %s, got %d",
+			fn.displayName(),
+			ll.arity(),
+			n)
+	}
+
+	for i, s := range ll.required {
+		in.bindVar(s, args[i])
+	}
+
+	var rest = args[len(ll.required):]
+
+	for _, p := range ll.optional {
+		var present = len(rest) > 0
+
+		if present {
+			in.bindVar(p.name, rest[0])
+			rest = rest[1:]
+		}
+
+		if err := in.bindParam(p, present); err != nil {
+			return err
+		}
+	}
+
+	if ll.rest != nil {
+		if err := in.alloc(len(rest)); err != nil {
+			return err
+		}
+
+		in.bindVar(*ll.rest, list(rest...))
+	}
+
+	if !ll.hasKey {
+		return nil
+	}
+
+	return in.bindKeyArgs(fn, ll, rest)
+} // func (in *Interpreter) bindArgs(fn *Function, ll *lambdaList, args []parser.LispValue) error
+
+// bindKeyArgs binds the &key parameters from the remaining arguments of a
+// call, which must be a property list of keywords and values.
+func (in *Interpreter) bindKeyArgs(fn *Function, ll *lambdaList, plist []parser.LispValue) error {
+	if len(plist)%2 != 0 {
+		return fmt.Errorf("Odd number of keyword arguments in call to %s: %d",
+			fn.displayName(),
+			len(plist))
+	}
+
+	var vals = make(map[string]parser.LispValue, len(plist)/2)
+
+	for i := 0; i < len(plist); i += 2 {
+		var kw, ok = plist[i].(parser.Symbol)
+
+		if !ok || !kw.IsKeyword() {
+			return fmt.Errorf("Expected a keyword in call to %s, got %s",
+				fn.displayName(),
+				plist[i])
+		} else if _, dup := vals[kw.Sym]; !dup {
+			// As in Common Lisp, the leftmost occurrence of a keyword wins.
+			vals[kw.Sym] = plist[i+1]
+		}
+	}
+
+	for _, p := range ll.key {
+		var val, present = vals[p.keyword.Sym]
+
+		if present {
+			in.bindVar(p.name, val)
+			delete(vals, p.keyword.Sym)
+		}
+
+		if err := in.bindParam(p, present); err != nil {
+			return err
+		}
+	}
+
+	if len(vals) > 0 && !ll.allowOtherKeys {
+		var unknown = make([]string, 0, len(vals))
+
+		for k := range vals {
+			unknown = append(unknown, k)
+		}
+
+		slices.Sort(unknown)
+
+		return fmt.Errorf("Unknown keyword argument(s) in call to %s: %s",
+			fn.displayName(),
+			strings.Join(unknown, ", "))
+	}
+
+	return nil
+} // func (in *Interpreter) bindKeyArgs(fn *Function, ll *lambdaList, plist []parser.LispValue) error
+
+// bindParam binds the default value of an optional or keyword parameter if
+// no argument was passed for it, and its supplied-p variable, if any.
+func (in *Interpreter) bindParam(p param, present bool) error {
+	if !present {
+		var (
+			err error
+			val parser.LispValue = sym("nil")
+		)
+
+		if p.init != nil {
+			if val, err = in.Eval(p.init); err != nil {
+				return fmt.Errorf("Error evaluating default value %s for parameter %s: %w",
+					p.init,
+					p.name,
+					err)
+			}
+		}
+
+		in.bindVar(p.name, val)
+	}
+
+	if p.supplied != nil {
+		if present {
+			in.bindVar(*p.supplied, sym("t"))
+		} else {
+			in.bindVar(*p.supplied, sym("nil"))
+		}
+	}
+
+	return nil
+} // func (in *Interpreter) bindParam(p param, present bool) error
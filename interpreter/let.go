@@ -0,0 +1,249 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/let.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 15:31:50 krylon>
+
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// LET, LET*, LETREC and LABELS push a fresh scope for their bindings and
+// evaluate their body in it. They are tailForms: The last form of the body
+// is evaluated by Eval in the scope of the bindings, and Eval restores the
+// outer scope when it returns.
+//
+//	(let ((var init)...) body...)        ; inits are evaluated outside
+//	(let* ((var init)...) body...)       ; each init sees the vars before it
+//	(letrec ((var init)...) body...)     ; inits see all vars
+//	(labels ((name (args...) body...)...) body...)
+//
+// In LET, LET* and LETREC, a binding can also be just a Symbol or (var),
+// which binds the variable to NIL.
+
+// binding is a parsed binding form of LET, LET* or LETREC.
+type binding struct {
+	name parser.Symbol
+	init parser.LispValue
+}
+
+// parseBindings parses the list of binding forms of a LET, LET* or LETREC.
+func parseBindings(form string, v parser.LispValue) ([]binding, error) {
+	var (
+		ok    bool
+		items []parser.LispValue
+		seen  = make(map[string]bool)
+	)
+
+	if items, ok = listItems(v); !ok {
+		return nil, fmt.Errorf("Bindings of %s must be a List, not a %s (%s)",
+			form,
+			v.Type(),
+			v)
+	}
+
+	var bindings = make([]binding, len(items))
+
+	for i, item := range items {
+		var (
+			b    = &bindings[i]
+			pair []parser.LispValue
+		)
+
+		if b.name, ok = item.(parser.Symbol); ok {
+			b.init = sym("nil")
+		} else if pair, ok = listItems(item); !ok || len(pair) == 0 || len(pair) > 2 {
+			return nil, fmt.Errorf("Malformed binding %s in %s: expected var, (var) or (var init)",
+				item,
+				form)
+		} else if b.name, ok = pair[0].(parser.Symbol); !ok {
+			return nil, fmt.Errorf("Malformed binding %s in %s: variable must be a Symbol, not a %s",
+				item,
+				form,
+				pair[0].Type())
+		} else if len(pair) == 2 {
+			b.init = pair[1]
+		} else {
+			b.init = sym("nil")
+		}
+
+		// LET* may bind the same variable more than once, each binding
+		// shadowing the one before.
+		if form == "LET*" {
+			delete(seen, b.name.Sym)
+		}
+
+		if err := checkParamName(b.name, seen); err != nil {
+			return nil, fmt.Errorf("Malformed binding %s in %s: %w",
+				item,
+				form,
+				err)
+		}
+	}
+
+	return bindings, nil
+} // func parseBindings(form string, v parser.LispValue) ([]binding, error)
+
+// reduceLet evaluates the bindings of a LET, LET*, LETREC or LABELS form in
+// a fresh scope, as well as all forms of the body but the last, which it
+// returns. The caller must restore the previous scope.
+func (in *Interpreter) reduceLet(form string, l *parser.ConsCell) (parser.LispValue, error) {
+	var (
+		err      error
+		bindings []binding
+	)
+
+	if cnt := l.Length(); cnt < 2 {
+		return nil, fmt.Errorf("Wrong number of arguments to %s: %d (expect >= 1)",
+			form,
+			cnt-1)
+	}
+
+	switch form {
+	case "LET":
+		if bindings, err = parseBindings(form, l.Rest().Car); err != nil {
+			return nil, err
+		}
+
+		var vals = make([]parser.LispValue, len(bindings))
+
+		for i, b := range bindings {
+			if vals[i], err = in.Eval(b.init); err != nil {
+				return nil, fmt.Errorf("Error evaluating binding of %s in LET: %w",
+					b.name,
+					err)
+			}
+		}
+
+		in.Env.Push()
+
+		for i, b := range bindings {
+			in.bindVar(b.name, vals[i])
+		}
+	case "LET*":
+		if bindings, err = parseBindings(form, l.Rest().Car); err != nil {
+			return nil, err
+		}
+
+		in.Env.Push()
+
+		for _, b := range bindings {
+			var val parser.LispValue
+
+			if val, err = in.Eval(b.init); err != nil {
+				return nil, fmt.Errorf("Error evaluating binding of %s in LET*: %w",
+					b.name,
+					err)
+			}
+
+			in.bindVar(b.name, val)
+		}
+	case "LETREC":
+		if bindings, err = parseBindings(form, l.Rest().Car); err != nil {
+			return nil, err
+		}
+
+		in.Env.Push()
+
+		for _, b := range bindings {
+			in.bindVar(b.name, sym("nil"))
+		}
+
+		for _, b := range bindings {
+			var val parser.LispValue
+
+			if val, err = in.Eval(b.init); err != nil {
+				return nil, fmt.Errorf("Error evaluating binding of %s in LETREC: %w",
+					b.name,
+					err)
+			}
+
+			if err = in.Env.Assign(b.name, val); err != nil {
+				return nil, err
+			}
+		}
+	case "LABELS":
+		if err = in.bindLabels(l.Rest().Car); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%s is not a binding form", form)
+	}
+
+	return in.reduceBody(l.Rest().Rest())
+} // func (in *Interpreter) reduceLet(form string, l *parser.ConsCell) (parser.LispValue, error)
+
+// bindLabels pushes a fresh scope and defines the local functions of a
+// LABELS form in it. Since the Functions are created in that scope, they
+// can call each other and themselves.
+func (in *Interpreter) bindLabels(v parser.LispValue) error {
+	var (
+		ok    bool
+		items []parser.LispValue
+		seen  = make(map[string]bool)
+	)
+
+	if items, ok = listItems(v); !ok {
+		return fmt.Errorf("Definitions of LABELS must be a List, not a %s (%s)",
+			v.Type(),
+			v)
+	}
+
+	in.Env.Push()
+
+	for _, item := range items {
+		var (
+			err  error
+			def  *parser.ConsCell
+			name parser.Symbol
+			fn   *Function
+		)
+
+		if def, ok = item.(*parser.ConsCell); !ok || def.Length() < 2 {
+			return fmt.Errorf("Malformed definition %s in LABELS: expected (name (args...) body...)",
+				item)
+		} else if name, ok = def.Car.(parser.Symbol); !ok {
+			return fmt.Errorf("Malformed definition %s in LABELS: name must be a Symbol, not a %s",
+				item,
+				def.Car.Type())
+		} else if err = checkParamName(name, seen); err != nil {
+			return fmt.Errorf("Malformed definition %s in LABELS: %w",
+				item,
+				err)
+		} else if fn, err = in.makeFunction(name.Sym, def.Rest().Car, def.Rest().Rest()); err != nil {
+			return fmt.Errorf("Invalid definition of local function %s: %w",
+				name,
+				err)
+		}
+
+		in.Env.Set(name, fn)
+	}
+
+	return nil
+} // func (in *Interpreter) bindLabels(v parser.LispValue) error
+
+// reduceBody evaluates all forms of body but the last, which it returns
+// for the caller to evaluate in tail position. An empty body reduces to
+// NIL.
+func (in *Interpreter) reduceBody(body *parser.ConsCell) (parser.LispValue, error) {
+	if body == nil {
+		return sym("nil"), nil
+	}
+
+	for ; body.Rest() != nil; body = body.Rest() {
+		if _, err := in.Eval(body.Car); err != nil {
+			if !isBlockReturn(err) {
+				in.log.Printf("[ERROR] Error evaluating expression %s: %s\n",
+					body.Car,
+					err.Error())
+			}
+			return nil, err
+		}
+	}
+
+	return body.Car, nil
+} // func (in *Interpreter) reduceBody(body *parser.ConsCell) (parser.LispValue, error)
@@ -0,0 +1,141 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/limits.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 12:27:05 krylon>
+
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// ErrStepLimit indicates that an evaluation took more steps than allowed.
+var ErrStepLimit = errors.New("Step limit exceeded")
+
+// ErrDepthLimit indicates that an evaluation recursed deeper than allowed.
+var ErrDepthLimit = errors.New("Recursion depth limit exceeded")
+
+// ErrConsLimit indicates that an evaluation allocated more cons cells than
+// allowed.
+var ErrConsLimit = errors.New("Cons limit exceeded")
+
+// ErrCancelled indicates that the Context of an evaluation was cancelled or
+// its deadline passed. The error also wraps the error of the Context.
+var ErrCancelled = errors.New("Evaluation cancelled")
+
+// ctxCheckInterval is the number of steps between checks whether the
+// Context of an evaluation is done.
+const ctxCheckInterval = 256
+
+// Limits restricts the resources a single call to EvalContext may use.
+// A limit of zero means no limit.
+//
+// A step is one iteration of Eval, or one instruction of the VM. The depth
+// is the nesting of calls to Eval and of frames on the VM's stack; tail
+// calls do not count. Cons cells are counted when they are created by
+// CONS, LIST, &REST parameters or QUASIQUOTE.
+type Limits struct {
+	MaxSteps  int
+	MaxDepth  int
+	MaxConses int
+}
+
+// budget keeps track of the resources used by an evaluation.
+type budget struct {
+	ctx    context.Context
+	limits Limits
+	steps  int
+	depth  int
+	conses int
+}
+
+// EvalContext evaluates v like Eval, but gives up as soon as ctx is done
+// or the evaluation exceeds the Interpreter's Limits. Errors caused by
+// either cannot be caught by HANDLER-CASE or IGNORE-ERRORS.
+func (in *Interpreter) EvalContext(ctx context.Context, v parser.LispValue) (parser.LispValue, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCancelled, err)
+	}
+
+	var prev = in.budget
+
+	in.budget = &budget{ctx: ctx, limits: in.Limits}
+	defer func() { in.budget = prev }()
+
+	return in.Eval(v)
+} // func (in *Interpreter) EvalContext(ctx context.Context, v parser.LispValue) (parser.LispValue, error)
+
+// step counts one step of the current evaluation, and checks periodically
+// if its Context is done.
+func (in *Interpreter) step() error {
+	var b = in.budget
+
+	if b == nil {
+		return nil
+	}
+
+	b.steps++
+
+	if b.limits.MaxSteps > 0 && b.steps > b.limits.MaxSteps {
+		return fmt.Errorf("%w: more than %d steps", ErrStepLimit, b.limits.MaxSteps)
+	} else if b.steps%ctxCheckInterval == 0 {
+		if err := b.ctx.Err(); err != nil {
+			return fmt.Errorf("%w: %w", ErrCancelled, err)
+		}
+	}
+
+	return nil
+} // func (in *Interpreter) step() error
+
+// enter increases the recursion depth of the current evaluation. If it
+// returns nil, the caller must call leave when it is done.
+func (in *Interpreter) enter() error {
+	var b = in.budget
+
+	if b == nil {
+		return nil
+	} else if b.limits.MaxDepth > 0 && b.depth >= b.limits.MaxDepth {
+		return fmt.Errorf("%w: more than %d levels", ErrDepthLimit, b.limits.MaxDepth)
+	}
+
+	b.depth++
+	return nil
+} // func (in *Interpreter) enter() error
+
+// leave decreases the recursion depth of the current evaluation.
+func (in *Interpreter) leave() {
+	if in.budget != nil {
+		in.budget.depth--
+	}
+} // func (in *Interpreter) leave()
+
+// alloc counts n newly created cons cells against the current evaluation.
+func (in *Interpreter) alloc(n int) error {
+	var b = in.budget
+
+	if b == nil {
+		return nil
+	}
+
+	b.conses += n
+
+	if b.limits.MaxConses > 0 && b.conses > b.limits.MaxConses {
+		return fmt.Errorf("%w: more than %d cons cells", ErrConsLimit, b.limits.MaxConses)
+	}
+
+	return nil
+} // func (in *Interpreter) alloc(n int) error
+
+// isAbort returns true if err means the evaluation must be given up, so no
+// handler may catch it.
+func isAbort(err error) bool {
+	return errors.Is(err, ErrCancelled) ||
+		errors.Is(err, ErrStepLimit) ||
+		errors.Is(err, ErrDepthLimit) ||
+		errors.Is(err, ErrConsLimit)
+} // func isAbort(err error) bool
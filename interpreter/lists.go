@@ -0,0 +1,542 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/lists.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 22:40:17 krylon>
+
+package interpreter
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// The list library. Functions that only take Lists apart are primitives,
+// functions that build new Lists or call other functions are special forms,
+// so they can count the cons cells they allocate.
+//
+// None of these functions modify their arguments. APPEND shares its last
+// argument with the result, REVERSE, MAPCAR, REMOVE-IF and SORT always
+// return fresh Lists.
+//
+// Functions passed to MAPCAR, REMOVE-IF, REDUCE, SORT, FUNCALL and APPLY
+// can be Functions, Builtins, Closures or Symbols naming one of them or a
+// primitive, e.g. (reduce '+ lst).
+
+// cxrNames returns the names of the accessors CAAR to CDDDDR.
+func cxrNames() []string {
+	var (
+		names []string
+		paths = []string{"A", "D"}
+	)
+
+	for depth := 2; depth <= 4; depth++ {
+		var longer = make([]string, 0, len(paths)*2)
+
+		for _, p := range paths {
+			longer = append(longer, "A"+p, "D"+p)
+		}
+
+		paths = longer
+
+		for _, p := range paths {
+			names = append(names, "C"+p+"R")
+		}
+	}
+
+	return names
+} // func cxrNames() []string
+
+// arity checks that a function got at least minArgs and at most maxArgs
+// arguments. A negative maxArgs means there is no upper limit.
+func arity(form string, args []parser.LispValue, minArgs, maxArgs int) error {
+	if len(args) < minArgs || (maxArgs >= 0 && len(args) > maxArgs) {
+		var expect string
+
+		switch {
+		case minArgs == maxArgs:
+			expect = fmt.Sprintf("%d", minArgs)
+		case maxArgs < 0:
+			expect = fmt.Sprintf(">= %d", minArgs)
+		default:
+			expect = fmt.Sprintf("%d to %d", minArgs, maxArgs)
+		}
+
+		return fmt.Errorf("Wrong number of arguments to %s: %d (expected %s)",
+			form,
+			len(args),
+			expect)
+	}
+
+	return nil
+} // func arity(form string, args []parser.LispValue, minArgs, maxArgs int) error
+
+// properList returns the elements of v, which must be a proper List.
+func properList(form string, v parser.LispValue) ([]parser.LispValue, error) {
+	var items, ok = listItems(v)
+
+	if !ok {
+		return nil, fmt.Errorf("%w: Argument to %s must be a proper List, not %s",
+			ErrType,
+			form,
+			v)
+	}
+
+	return items, nil
+} // func properList(form string, v parser.LispValue) ([]parser.LispValue, error)
+
+// listIndex returns the value of v, which must be a non-negative Integer.
+func listIndex(form string, v parser.LispValue) (int64, error) {
+	var i, ok = v.(parser.Integer)
+
+	if !ok || i.Int < 0 {
+		return 0, fmt.Errorf("%w: Index for %s must be a non-negative Integer, not %s",
+			ErrType,
+			form,
+			v)
+	}
+
+	return i.Int, nil
+} // func listIndex(form string, v parser.LispValue) (int64, error)
+
+// cxr implements the accessors CAAR to CDDDDR, which combine up to four
+// CARs and CDRs. The letters between C and R are applied from right to
+// left, so CADR is the CAR of the CDR.
+func cxr(form string, args []parser.LispValue) (parser.LispValue, error) {
+	if err := arity(form, args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	var v = args[0]
+
+	for i := len(form) - 2; i > 0; i-- {
+		var cell, err = consArg(form, v)
+
+		if err != nil {
+			return nil, err
+		} else if cell == nil {
+			return sym("nil"), nil
+		} else if form[i] == 'A' {
+			v = cell.Car
+		} else {
+			v = cell.Tail()
+		}
+	}
+
+	return v, nil
+} // func cxr(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// listNth implements NTH and NTHCDR. Indices past the end of the List
+// return NIL.
+func listNth(form string, args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err  error
+		n    int64
+		cell *parser.ConsCell
+	)
+
+	if err = arity(form, args, 2, 2); err != nil {
+		return nil, err
+	} else if n, err = listIndex(form, args[0]); err != nil {
+		return nil, err
+	}
+
+	var v = args[1]
+
+	for ; n > 0; n-- {
+		if cell, err = consArg(form, v); err != nil {
+			return nil, err
+		} else if cell == nil {
+			return sym("nil"), nil
+		}
+
+		v = cell.Tail()
+	}
+
+	if form == "NTHCDR" {
+		return v, nil
+	} else if cell, err = consArg(form, v); err != nil {
+		return nil, err
+	} else if cell == nil {
+		return sym("nil"), nil
+	}
+
+	return cell.Car, nil
+} // func listNth(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// listLength implements LENGTH, which works on proper Lists and Strings.
+func listLength(form string, args []parser.LispValue) (parser.LispValue, error) {
+	if err := arity(form, args, 1, 1); err != nil {
+		return nil, err
+	} else if s, ok := args[0].(parser.String); ok {
+		return parser.Integer{Int: int64(utf8.RuneCountInString(s.Str))}, nil
+	}
+
+	var items, err = properList(form, args[0])
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parser.Integer{Int: int64(len(items))}, nil
+} // func listLength(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// listLast implements LAST, which returns the last n ConsCells of a List,
+// by default one.
+func listLast(form string, args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err   error
+		n     int64 = 1
+		cells []*parser.ConsCell
+	)
+
+	if err = arity(form, args, 1, 2); err != nil {
+		return nil, err
+	} else if _, err = consArg(form, args[0]); err != nil {
+		return nil, err
+	} else if len(args) == 2 {
+		if n, err = listIndex(form, args[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	var v = args[0]
+
+	for cell, ok := v.(*parser.ConsCell); ok; cell, ok = v.(*parser.ConsCell) {
+		cells = append(cells, cell)
+		v = cell.Tail()
+	}
+
+	if n == 0 {
+		return v, nil
+	} else if n >= int64(len(cells)) {
+		return args[0], nil
+	}
+
+	return cells[int64(len(cells))-n], nil
+} // func listLast(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// listFind implements MEMBER and ASSOC. MEMBER returns the tail of the List
+// that starts with the item, ASSOC returns the first entry of an
+// association list whose CAR is the key. Both compare with EQL.
+func listFind(form string, args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err  error
+		cell *parser.ConsCell
+	)
+
+	if err = arity(form, args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	for v := args[1]; ; v = cell.Tail() {
+		if cell, err = consArg(form, v); err != nil {
+			return nil, err
+		} else if cell == nil {
+			return sym("nil"), nil
+		} else if form == "MEMBER" {
+			if parser.Eql(cell.Car, args[0]) {
+				return cell, nil
+			}
+
+			continue
+		}
+
+		var entry *parser.ConsCell
+
+		if entry, err = consArg(form, cell.Car); err != nil {
+			return nil, err
+		} else if entry != nil && parser.Eql(entry.Car, args[0]) {
+			return entry, nil
+		}
+	}
+} // func listFind(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// funcall calls fn with the given, already evaluated, arguments. fn can be
+// a Function, Builtin or Closure, or a Symbol that names a primitive or is
+// bound to one of those.
+func (in *Interpreter) funcall(fn parser.LispValue, args []parser.LispValue) (parser.LispValue, error) {
+	switch f := fn.(type) {
+	case *Function:
+		return in.apply(f, args)
+	case *Builtin:
+		return f.call(args)
+	case *Closure:
+		return in.execute(f, args)
+	case parser.Symbol:
+		if prim, isPrim := primitives[f.Sym]; isPrim {
+			return prim(f.Sym, args)
+		} else if val, ok := in.Env.Lookup(f); ok {
+			switch val.(type) {
+			case *Function, *Builtin, *Closure:
+				return in.funcall(val, args)
+			}
+		}
+
+		return nil, fmt.Errorf("%w: %s does not name a function",
+			ErrUndefined,
+			f)
+	default:
+		return nil, fmt.Errorf("%w: Cannot call a %s (%s), it is not a function",
+			ErrType,
+			fn.Type(),
+			fn)
+	}
+} // func (in *Interpreter) funcall(fn parser.LispValue, args []parser.LispValue) (parser.LispValue, error)
+
+// freshList creates a new List of the given items, counting its cons cells
+// against the current evaluation.
+func (in *Interpreter) freshList(items []parser.LispValue) (parser.LispValue, error) {
+	if err := in.alloc(len(items)); err != nil {
+		return nil, err
+	}
+
+	return list(items...), nil
+} // func (in *Interpreter) freshList(items []parser.LispValue) (parser.LispValue, error)
+
+// callFunction implements FUNCALL and APPLY. (apply fn args... list)
+// calls fn with args, followed by the elements of list.
+func (in *Interpreter) callFunction(form string, args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err    error
+		spread []parser.LispValue
+	)
+
+	if form == "FUNCALL" {
+		if err = arity(form, args, 1, -1); err != nil {
+			return nil, err
+		}
+
+		return in.funcall(args[0], args[1:])
+	} else if err = arity(form, args, 2, -1); err != nil {
+		return nil, err
+	} else if spread, err = properList(form, args[len(args)-1]); err != nil {
+		return nil, err
+	}
+
+	var fnArgs = make([]parser.LispValue, 0, len(args)-2+len(spread))
+
+	fnArgs = append(fnArgs, args[1:len(args)-1]...)
+	fnArgs = append(fnArgs, spread...)
+
+	return in.funcall(args[0], fnArgs)
+} // func (in *Interpreter) callFunction(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// listFunction implements the list functions that allocate new Lists or
+// call a function: APPEND, REVERSE, MAPCAR, REMOVE-IF, REMOVE-IF-NOT,
+// REDUCE and SORT.
+func (in *Interpreter) listFunction(form string, args []parser.LispValue) (parser.LispValue, error) {
+	var err error
+
+	switch form {
+	case "APPEND":
+		return in.listAppend(args)
+	case "REVERSE":
+		var items []parser.LispValue
+
+		if err = arity(form, args, 1, 1); err != nil {
+			return nil, err
+		} else if items, err = properList(form, args[0]); err != nil {
+			return nil, err
+		}
+
+		var rev = make([]parser.LispValue, len(items))
+
+		for i, item := range items {
+			rev[len(items)-1-i] = item
+		}
+
+		return in.freshList(rev)
+	case "MAPCAR":
+		return in.mapcar(args)
+	case "REMOVE-IF", "REMOVE-IF-NOT":
+		return in.removeIf(form, args)
+	case "REDUCE":
+		return in.reduce(args)
+	case "SORT":
+		return in.sortList(args)
+	default:
+		return nil, fmt.Errorf("%s is not a list function", form)
+	}
+} // func (in *Interpreter) listFunction(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// listAppend implements APPEND. All arguments but the last are copied, the
+// last one becomes the tail of the result and may be any value.
+func (in *Interpreter) listAppend(args []parser.LispValue) (parser.LispValue, error) {
+	if len(args) == 0 {
+		return sym("nil"), nil
+	}
+
+	var items []parser.LispValue
+
+	for _, arg := range args[:len(args)-1] {
+		var part, err = properList("APPEND", arg)
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, part...)
+	}
+
+	if err := in.alloc(len(items)); err != nil {
+		return nil, err
+	}
+
+	return parser.MakeDotted(args[len(args)-1], items...), nil
+} // func (in *Interpreter) listAppend(args []parser.LispValue) (parser.LispValue, error)
+
+// mapcar implements MAPCAR, which calls a function with the first elements
+// of all Lists, then with the second ones, and so on, up to the end of the
+// shortest List, and returns a List of the results.
+func (in *Interpreter) mapcar(args []parser.LispValue) (parser.LispValue, error) {
+	if err := arity("MAPCAR", args, 2, -1); err != nil {
+		return nil, err
+	}
+
+	var (
+		lists = make([][]parser.LispValue, len(args)-1)
+		n     = -1
+	)
+
+	for i, arg := range args[1:] {
+		var err error
+
+		if lists[i], err = properList("MAPCAR", arg); err != nil {
+			return nil, err
+		} else if n < 0 || len(lists[i]) < n {
+			n = len(lists[i])
+		}
+	}
+
+	var res = make([]parser.LispValue, n)
+
+	for i := range res {
+		var (
+			err    error
+			fnArgs = make([]parser.LispValue, len(lists))
+		)
+
+		for j, lst := range lists {
+			fnArgs[j] = lst[i]
+		}
+
+		if res[i], err = in.funcall(args[0], fnArgs); err != nil {
+			return nil, err
+		}
+	}
+
+	return in.freshList(res)
+} // func (in *Interpreter) mapcar(args []parser.LispValue) (parser.LispValue, error)
+
+// removeIf implements REMOVE-IF and REMOVE-IF-NOT, which return a new List
+// of the elements for which the predicate is false or true, respectively.
+func (in *Interpreter) removeIf(form string, args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err   error
+		items []parser.LispValue
+		keep  []parser.LispValue
+	)
+
+	if err = arity(form, args, 2, 2); err != nil {
+		return nil, err
+	} else if items, err = properList(form, args[1]); err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		var res parser.LispValue
+
+		if res, err = in.funcall(args[0], []parser.LispValue{item}); err != nil {
+			return nil, err
+		} else if asBool(res) == (form == "REMOVE-IF-NOT") {
+			keep = append(keep, item)
+		}
+	}
+
+	return in.freshList(keep)
+} // func (in *Interpreter) removeIf(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// reduce implements REDUCE, which combines the elements of a List from left
+// to right with a function of two arguments:
+//
+//	(reduce fn list [:initial-value value])
+//
+// Without an initial value, REDUCE returns the only element of a List of
+// length one, and the result of calling fn without arguments for the empty
+// List.
+func (in *Interpreter) reduce(args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err   error
+		acc   parser.LispValue
+		items []parser.LispValue
+	)
+
+	if len(args) != 2 && len(args) != 4 {
+		return nil, fmt.Errorf("Wrong number of arguments to REDUCE: %d (expected 2 or 4)",
+			len(args))
+	} else if items, err = properList("REDUCE", args[1]); err != nil {
+		return nil, err
+	} else if len(args) == 4 {
+		if kw, ok := args[2].(parser.Symbol); !ok || kw.Sym != ":INITIAL-VALUE" {
+			return nil, fmt.Errorf("Invalid keyword argument to REDUCE: %s", args[2])
+		}
+
+		acc = args[3]
+	} else if len(items) == 0 {
+		return in.funcall(args[0], nil)
+	} else {
+		acc, items = items[0], items[1:]
+	}
+
+	for _, item := range items {
+		if acc, err = in.funcall(args[0], []parser.LispValue{acc, item}); err != nil {
+			return nil, err
+		}
+	}
+
+	return acc, nil
+} // func (in *Interpreter) reduce(args []parser.LispValue) (parser.LispValue, error)
+
+// sortList implements SORT, which returns a new List of the elements of a
+// List, ordered by a predicate that is true if its first argument belongs
+// before its second. The sort is stable.
+func (in *Interpreter) sortList(args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err   error
+		items []parser.LispValue
+	)
+
+	if err = arity("SORT", args, 2, 2); err != nil {
+		return nil, err
+	} else if items, err = properList("SORT", args[0]); err != nil {
+		return nil, err
+	}
+
+	var sorted = make([]parser.LispValue, len(items))
+
+	copy(sorted, items)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if err != nil {
+			return false
+		}
+
+		var res parser.LispValue
+
+		if res, err = in.funcall(args[1], []parser.LispValue{sorted[i], sorted[j]}); err != nil {
+			return false
+		}
+
+		return asBool(res)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return in.freshList(sorted)
+} // func (in *Interpreter) sortList(args []parser.LispValue) (parser.LispValue, error)
@@ -0,0 +1,679 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/loop.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 18:47:02 krylon>
+
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// BLOCK establishes a named exit point, RETURN-FROM leaves the innermost
+// BLOCK of that name it appears in, and RETURN leaves the innermost block
+// named NIL. WHILE, DOTIMES, DOLIST and LOOP are implicitly enclosed in a
+// block named NIL.
+//
+// Blocks are lexical: a function called from a BLOCK cannot return from it,
+// but a closure created inside one can, as long as the block has not been
+// left yet. RETURN-FROM without such a block signals a CONTROL-ERROR.
+//
+//	(block name body...)
+//	(return-from name [value])
+//	(while test body...)
+//	(dotimes (var count [result]) body...)
+//	(dolist (var list [result]) body...)
+//	(loop body...)                        ; loops until RETURN
+//	(loop clauses...)
+//
+// The clauses LOOP understands are
+//
+//	for var in list
+//	for var from start [to end] [by step]
+//	collect form
+//	sum form
+//	do forms...
+//	when test {collect form | sum form | do forms...}
+//	finally forms...
+//
+// The loop ends as soon as one of its FOR clauses runs out of values, and
+// returns the list of the collected values, or the sum, unless a FINALLY
+// form returns something else with RETURN. FINALLY forms are evaluated
+// after the last iteration, outside the scope of the loop variables.
+//
+// The iteration variables are bound afresh for every iteration, so closures
+// created in the body capture the value of that iteration.
+
+// blockTag identifies one activation of a BLOCK. It is kept in the scope
+// the body of the block is evaluated in, so RETURN-FROM finds it through
+// the lexical environment.
+type blockTag struct {
+	name   *parser.Atom
+	active bool
+}
+
+// blockReturn is the error RETURN-FROM uses to unwind the Go stack up to
+// the BLOCK it leaves.
+type blockReturn struct {
+	tag *blockTag
+	val parser.LispValue
+}
+
+func (r *blockReturn) Error() string {
+	return fmt.Sprintf("RETURN-FROM %s outside of its block", r.tag.name.Name())
+} // func (r *blockReturn) Error() string
+
+// isBlockReturn returns true if err is a RETURN-FROM on its way to its
+// BLOCK. HANDLER-CASE must let those pass.
+func isBlockReturn(err error) bool {
+	var r *blockReturn
+
+	return errors.As(err, &r)
+} // func isBlockReturn(err error) bool
+
+// block calls body as the body of a BLOCK named name.
+func (in *Interpreter) block(name parser.Symbol, body func() (parser.LispValue, error)) (parser.LispValue, error) {
+	var (
+		r    *blockReturn
+		tag  = &blockTag{name: name.Atom(), active: true}
+		prev = in.Env.enterBlock(tag)
+	)
+
+	var val, err = body()
+
+	tag.active = false
+	in.Env.Leave(prev)
+
+	if errors.As(err, &r) && r.tag == tag {
+		return r.val, nil
+	}
+
+	return val, err
+} // func (in *Interpreter) block(name parser.Symbol, body func() (parser.LispValue, error)) (parser.LispValue, error)
+
+// returnFrom evaluates the value of a RETURN-FROM and returns the error
+// that carries it to the BLOCK.
+func (in *Interpreter) returnFrom(name parser.Symbol, form parser.LispValue) error {
+	var (
+		err error
+		val parser.LispValue
+		tag = in.Env.scope.findBlock(name.Atom())
+	)
+
+	if tag == nil {
+		return fmt.Errorf("%w: RETURN-FROM %s outside of a block of that name",
+			ErrControl,
+			name)
+	} else if val, err = in.Eval(form); err != nil {
+		return err
+	} else if !tag.active {
+		return fmt.Errorf("%w: RETURN-FROM %s after its block has been left",
+			ErrControl,
+			name)
+	}
+
+	return &blockReturn{tag: tag, val: val}
+} // func (in *Interpreter) returnFrom(name parser.Symbol, form parser.LispValue) error
+
+// while evaluates the body of a WHILE form for as long as its test is true.
+func (in *Interpreter) while(l *parser.ConsCell) (parser.LispValue, error) {
+	if l.Rest() == nil {
+		return nil, fmt.Errorf("WHILE needs a test form")
+	}
+
+	for {
+		var (
+			err error
+			val parser.LispValue
+		)
+
+		if err = in.step(); err != nil {
+			return nil, err
+		} else if val, err = in.Eval(l.Rest().Car); err != nil {
+			return nil, err
+		} else if !asBool(val) {
+			return sym("nil"), nil
+		} else if _, err = in.evalBody(l.Rest().Rest()); err != nil {
+			return nil, err
+		}
+	}
+} // func (in *Interpreter) while(l *parser.ConsCell) (parser.LispValue, error)
+
+// doLoop implements DOTIMES and DOLIST.
+func (in *Interpreter) doLoop(form string, l *parser.ConsCell) (parser.LispValue, error) {
+	var (
+		err    error
+		ok     bool
+		name   parser.Symbol
+		spec   []parser.LispValue
+		val    parser.LispValue
+		values []parser.LispValue
+		last   parser.LispValue
+		count  parser.Integer
+		what   = "count"
+	)
+
+	if form == "DOLIST" {
+		what = "list"
+	}
+
+	if l.Rest() == nil {
+		return nil, fmt.Errorf("%s needs a specification (var %s [result])",
+			form,
+			what)
+	} else if spec, ok = listItems(l.Rest().Car); !ok || len(spec) < 2 || len(spec) > 3 {
+		return nil, fmt.Errorf("Malformed specification %s in %s: expected (var %s [result])",
+			l.Rest().Car,
+			form,
+			what)
+	} else if name, err = variableName(form, spec[0]); err != nil {
+		return nil, err
+	} else if val, err = in.Eval(spec[1]); err != nil {
+		return nil, err
+	}
+
+	if form == "DOTIMES" {
+		if count, ok = val.(parser.Integer); !ok {
+			return nil, fmt.Errorf("%w: Count in DOTIMES must be an Integer, not a %s (%s)",
+				ErrType,
+				val.Type(),
+				val)
+		}
+
+		last = count
+	} else if values, ok = listItems(val); !ok {
+		return nil, fmt.Errorf("%w: DOLIST needs a List, not a %s (%s)",
+			ErrType,
+			val.Type(),
+			val)
+	} else {
+		last = sym("nil")
+	}
+
+	var (
+		entry = in.Env.scope
+		mark  = len(in.dynamic)
+	)
+
+	defer in.Env.Leave(entry)
+	defer in.unwindDynamic(mark)
+
+	for i := 0; ; i++ {
+		if err = in.step(); err != nil {
+			return nil, err
+		}
+
+		if form == "DOTIMES" {
+			if int64(i) >= count.Int {
+				break
+			}
+
+			val = parser.Integer{Int: int64(i)}
+		} else if i < len(values) {
+			val = values[i]
+		} else {
+			break
+		}
+
+		in.Env.Push()
+		in.bindVar(name, val)
+
+		if _, err = in.evalBody(l.Rest().Rest()); err != nil {
+			return nil, err
+		}
+
+		in.unwindDynamic(mark)
+		in.Env.Leave(entry)
+	}
+
+	if len(spec) < 3 {
+		return sym("nil"), nil
+	}
+
+	in.Env.Push()
+	in.bindVar(name, last)
+
+	return in.Eval(spec[2])
+} // func (in *Interpreter) doLoop(form string, l *parser.ConsCell) (parser.LispValue, error)
+
+// loopVar is a FOR clause of a LOOP.
+type loopVar struct {
+	name         parser.Symbol
+	list         parser.LispValue
+	from, to, by parser.LispValue
+}
+
+// loopAction is a COLLECT, SUM or DO clause of a LOOP, with the test of
+// the WHEN clause it is part of, if any.
+type loopAction struct {
+	kind  string
+	test  parser.LispValue
+	forms []parser.LispValue
+}
+
+// loopSpec is a parsed LOOP form.
+type loopSpec struct {
+	vars    []loopVar
+	actions []loopAction
+	finally []parser.LispValue
+	result  string
+}
+
+// loopParser parses the clauses of a LOOP form.
+type loopParser struct {
+	items []parser.LispValue
+	pos   int
+}
+
+// keyword returns the name of the next item, if it is a Symbol.
+func (p *loopParser) keyword() (string, bool) {
+	if p.pos >= len(p.items) {
+		return "", false
+	}
+
+	var s, ok = p.items[p.pos].(parser.Symbol)
+
+	return s.Sym, ok
+} // func (p *loopParser) keyword() (string, bool)
+
+// next returns the next item. after is the clause keyword the item belongs
+// to, for the error message if there are no more items.
+func (p *loopParser) next(after string) (parser.LispValue, error) {
+	if p.pos >= len(p.items) {
+		return nil, fmt.Errorf("Missing form after %s in LOOP", after)
+	}
+
+	p.pos++
+	return p.items[p.pos-1], nil
+} // func (p *loopParser) next(after string) (parser.LispValue, error)
+
+// compound returns the Lists up to the next clause keyword, of which there
+// must be at least one.
+func (p *loopParser) compound(after string) ([]parser.LispValue, error) {
+	var forms []parser.LispValue
+
+	for p.pos < len(p.items) {
+		if _, isList := p.items[p.pos].(*parser.ConsCell); !isList {
+			break
+		}
+
+		forms = append(forms, p.items[p.pos])
+		p.pos++
+	}
+
+	if len(forms) == 0 {
+		return nil, fmt.Errorf("Missing form after %s in LOOP", after)
+	}
+
+	return forms, nil
+} // func (p *loopParser) compound(after string) ([]parser.LispValue, error)
+
+// parseLoop parses the clauses of a LOOP form.
+func parseLoop(items []parser.LispValue) (*loopSpec, error) {
+	var (
+		spec = new(loopSpec)
+		p    = &loopParser{items: items}
+	)
+
+	for p.pos < len(p.items) {
+		var (
+			err    error
+			kw, ok = p.keyword()
+		)
+
+		if !ok {
+			return nil, fmt.Errorf("Expected a LOOP clause, not %s", p.items[p.pos])
+		}
+
+		p.pos++
+
+		switch kw {
+		case "FOR":
+			var v loopVar
+
+			if v, err = p.parseFor(); err != nil {
+				return nil, err
+			}
+
+			spec.vars = append(spec.vars, v)
+		case "COLLECT", "SUM", "DO":
+			var a = loopAction{kind: kw}
+
+			if err = p.parseAction(&a, spec); err != nil {
+				return nil, err
+			}
+
+			spec.actions = append(spec.actions, a)
+		case "WHEN":
+			var a loopAction
+
+			if a.test, err = p.next(kw); err != nil {
+				return nil, err
+			} else if a.kind, ok = p.keyword(); !ok || (a.kind != "COLLECT" && a.kind != "SUM" && a.kind != "DO") {
+				return nil, fmt.Errorf("WHEN in LOOP must be followed by COLLECT, SUM or DO")
+			}
+
+			p.pos++
+
+			if err = p.parseAction(&a, spec); err != nil {
+				return nil, err
+			}
+
+			spec.actions = append(spec.actions, a)
+		case "FINALLY":
+			var forms []parser.LispValue
+
+			if forms, err = p.compound(kw); err != nil {
+				return nil, err
+			}
+
+			spec.finally = append(spec.finally, forms...)
+		default:
+			return nil, fmt.Errorf("Unsupported LOOP clause %s", kw)
+		}
+	}
+
+	return spec, nil
+} // func parseLoop(items []parser.LispValue) (*loopSpec, error)
+
+// parseFor parses the rest of a FOR clause.
+func (p *loopParser) parseFor() (loopVar, error) {
+	var (
+		err error
+		v   loopVar
+		val parser.LispValue
+	)
+
+	if val, err = p.next("FOR"); err != nil {
+		return v, err
+	} else if v.name, err = variableName("LOOP", val); err != nil {
+		return v, err
+	}
+
+	switch kw, _ := p.keyword(); kw {
+	case "IN":
+		p.pos++
+		v.list, err = p.next(kw)
+		return v, err
+	case "FROM":
+		p.pos++
+
+		if v.from, err = p.next(kw); err != nil {
+			return v, err
+		}
+	default:
+		return v, fmt.Errorf("FOR %s in LOOP must be followed by IN or FROM", v.name)
+	}
+
+	for {
+		switch kw, _ := p.keyword(); kw {
+		case "TO":
+			if v.to != nil {
+				return v, fmt.Errorf("Duplicate TO in FOR %s", v.name)
+			}
+
+			p.pos++
+
+			if v.to, err = p.next(kw); err != nil {
+				return v, err
+			}
+		case "BY":
+			if v.by != nil {
+				return v, fmt.Errorf("Duplicate BY in FOR %s", v.name)
+			}
+
+			p.pos++
+
+			if v.by, err = p.next(kw); err != nil {
+				return v, err
+			}
+		default:
+			return v, nil
+		}
+	}
+} // func (p *loopParser) parseFor() (loopVar, error)
+
+// parseAction parses the forms of a COLLECT, SUM or DO clause. A LOOP can
+// either collect or sum, but not both.
+func (p *loopParser) parseAction(a *loopAction, spec *loopSpec) error {
+	var err error
+
+	if a.kind == "DO" {
+		a.forms, err = p.compound(a.kind)
+		return err
+	} else if spec.result != "" && spec.result != a.kind {
+		return fmt.Errorf("LOOP cannot both %s and %s", spec.result, a.kind)
+	}
+
+	spec.result = a.kind
+	a.forms = make([]parser.LispValue, 1)
+	a.forms[0], err = p.next(a.kind)
+
+	return err
+} // func (p *loopParser) parseAction(a *loopAction, spec *loopSpec) error
+
+// loopState is the current state of a FOR clause while a LOOP runs.
+type loopState struct {
+	values       []parser.LispValue
+	cur, to, by  parser.LispValue
+	isRange, eol bool
+}
+
+// loop implements LOOP.
+func (in *Interpreter) loop(l *parser.ConsCell) (parser.LispValue, error) {
+	var (
+		err      error
+		spec     *loopSpec
+		isClause bool
+		items, _ = listItems(l.Tail())
+	)
+
+	if len(items) > 0 {
+		_, isClause = items[0].(parser.Symbol)
+	}
+
+	if !isClause {
+		for {
+			if err = in.step(); err != nil {
+				return nil, err
+			} else if _, err = in.evalBody(l.Rest()); err != nil {
+				return nil, err
+			}
+		}
+	} else if spec, err = parseLoop(items); err != nil {
+		return nil, err
+	}
+
+	var state = make([]loopState, len(spec.vars))
+
+	for i, v := range spec.vars {
+		if err = in.initLoopVar(v, &state[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		collected []parser.LispValue
+		sum       parser.LispValue = parser.Integer{Int: 0}
+		entry                      = in.Env.scope
+		mark                       = len(in.dynamic)
+	)
+
+	defer in.Env.Leave(entry)
+	defer in.unwindDynamic(mark)
+
+ITERATE:
+	for {
+		if err = in.step(); err != nil {
+			return nil, err
+		}
+
+		in.Env.Push()
+
+		for i, v := range spec.vars {
+			var val parser.LispValue
+
+			if val, err = in.stepLoopVar(&state[i]); err != nil {
+				return nil, err
+			} else if state[i].eol {
+				break ITERATE
+			}
+
+			in.bindVar(v.name, val)
+		}
+
+		for _, a := range spec.actions {
+			var val parser.LispValue
+
+			if a.test != nil {
+				if val, err = in.Eval(a.test); err != nil {
+					return nil, err
+				} else if !asBool(val) {
+					continue
+				}
+			}
+
+			for _, f := range a.forms {
+				if val, err = in.Eval(f); err != nil {
+					return nil, err
+				}
+			}
+
+			switch a.kind {
+			case "COLLECT":
+				if err = in.alloc(1); err != nil {
+					return nil, err
+				}
+
+				collected = append(collected, val)
+			case "SUM":
+				if sum, err = numAdd(sum, val); err != nil {
+					return nil, fmt.Errorf("Cannot SUM %s in LOOP: %w", val, err)
+				}
+			}
+		}
+
+		in.unwindDynamic(mark)
+		in.Env.Leave(entry)
+	}
+
+	in.unwindDynamic(mark)
+	in.Env.Leave(entry)
+
+	for _, f := range spec.finally {
+		if _, err = in.Eval(f); err != nil {
+			return nil, err
+		}
+	}
+
+	switch spec.result {
+	case "COLLECT":
+		return parser.MakeList(collected...), nil
+	case "SUM":
+		return sum, nil
+	default:
+		return sym("nil"), nil
+	}
+} // func (in *Interpreter) loop(l *parser.ConsCell) (parser.LispValue, error)
+
+// initLoopVar evaluates the forms of a FOR clause, before the first
+// iteration of the LOOP.
+func (in *Interpreter) initLoopVar(v loopVar, s *loopState) error {
+	var (
+		err error
+		ok  bool
+		val parser.LispValue
+	)
+
+	if v.list != nil {
+		if val, err = in.Eval(v.list); err != nil {
+			return err
+		} else if s.values, ok = listItems(val); !ok {
+			return fmt.Errorf("%w: FOR %s IN needs a List, not a %s (%s)",
+				ErrType,
+				v.name,
+				val.Type(),
+				val)
+		}
+
+		return nil
+	}
+
+	s.isRange = true
+	s.by = parser.Integer{Int: 1}
+
+	if s.cur, err = in.Eval(v.from); err != nil {
+		return err
+	} else if !isNumber(s.cur) {
+		return fmt.Errorf("%w: FROM in FOR %s must be a number, not a %s (%s)",
+			ErrType,
+			v.name,
+			s.cur.Type(),
+			s.cur)
+	} else if v.to != nil {
+		if s.to, err = in.Eval(v.to); err != nil {
+			return err
+		} else if !isNumber(s.to) {
+			return fmt.Errorf("%w: TO in FOR %s must be a number, not a %s (%s)",
+				ErrType,
+				v.name,
+				s.to.Type(),
+				s.to)
+		}
+	}
+
+	if v.by != nil {
+		var cmp int
+
+		if s.by, err = in.Eval(v.by); err != nil {
+			return err
+		} else if cmp, err = numCompare("BY", s.by, parser.Integer{Int: 0}); err != nil {
+			return err
+		} else if cmp != 1 {
+			return fmt.Errorf("BY in FOR %s must be positive, not %s", v.name, s.by)
+		}
+	}
+
+	return nil
+} // func (in *Interpreter) initLoopVar(v loopVar, s *loopState) error
+
+// stepLoopVar returns the value of a FOR variable for the next iteration.
+// If there is none, it sets s.eol.
+func (in *Interpreter) stepLoopVar(s *loopState) (parser.LispValue, error) {
+	if !s.isRange {
+		if len(s.values) == 0 {
+			s.eol = true
+			return nil, nil
+		}
+
+		var val = s.values[0]
+
+		s.values = s.values[1:]
+		return val, nil
+	}
+
+	var (
+		err error
+		cmp int
+		val = s.cur
+	)
+
+	if s.to != nil {
+		if cmp, err = numCompare("TO", val, s.to); err != nil {
+			return nil, err
+		} else if cmp != -1 && cmp != 0 {
+			s.eol = true
+			return nil, nil
+		}
+	}
+
+	if s.cur, err = numAdd(s.cur, s.by); err != nil {
+		return nil, err
+	}
+
+	return val, nil
+} // func (in *Interpreter) stepLoopVar(s *loopState) (parser.LispValue, error)
@@ -0,0 +1,163 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/macro.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 17. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-17 23:20:36 krylon>
+
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/blicero/krylisp/parser"
+	"github.com/blicero/krylisp/types"
+)
+
+// Macro is a user-defined macro. When a list whose head is bound to a Macro
+// is evaluated, the expander is called with the unevaluated arguments,
+// and the form it returns is evaluated in place of the original one.
+type Macro struct {
+	expander *Function
+}
+
+func (m *Macro) String() string {
+	return fmt.Sprintf("#<MACRO %s>", m.expander.displayName())
+} // func (m *Macro) String() string
+
+// Type returns the type of the receiver, i.e. types.Macro
+func (m *Macro) Type() types.Type { return types.Macro }
+
+// Equal compares the receiver to another LispValue for equality.
+// Macros are only equal to themselves.
+func (m *Macro) Equal(other parser.LispValue) bool {
+	var o, ok = other.(*Macro)
+
+	return ok && o == m
+} // func (m *Macro) Equal(other parser.LispValue) bool
+
+// macroFor returns the Macro a form is a call to, if any.
+func (in *Interpreter) macroFor(form parser.LispValue) (*Macro, bool) {
+	var (
+		ok   bool
+		l    *parser.ConsCell
+		head parser.Symbol
+		val  parser.LispValue
+		m    *Macro
+	)
+
+	if l, ok = form.(*parser.ConsCell); !ok {
+		return nil, false
+	} else if head, ok = l.Car.(parser.Symbol); !ok || isSpecial(head) {
+		return nil, false
+	} else if val, ok = in.Env.Lookup(head); !ok {
+		return nil, false
+	} else if m, ok = val.(*Macro); !ok {
+		return nil, false
+	}
+
+	return m, true
+} // func (in *Interpreter) macroFor(form parser.LispValue) (*Macro, bool)
+
+// expandMacro calls the expander of m with the unevaluated arguments of form.
+func (in *Interpreter) expandMacro(m *Macro, form *parser.ConsCell) (parser.LispValue, error) {
+	var (
+		err error
+		exp parser.LispValue
+		raw []parser.LispValue
+	)
+
+	for c := form.Rest(); c != nil; c = c.Rest() {
+		raw = append(raw, c.Car)
+	}
+
+	if exp, err = in.apply(m.expander, raw); err != nil {
+		return nil, fmt.Errorf("Error expanding macro %s: %w",
+			m.expander.displayName(),
+			err)
+	}
+
+	in.log.Printf("[TRACE] Expanded %s to %s\n",
+		form,
+		exp)
+
+	return exp, nil
+} // func (in *Interpreter) expandMacro(m *Macro, form *parser.ConsCell) (parser.LispValue, error)
+
+// macroexpand1 expands form once if it is a macro call. The second return
+// value indicates whether an expansion took place.
+func (in *Interpreter) macroexpand1(form parser.LispValue) (parser.LispValue, bool, error) {
+	var m, ok = in.macroFor(form)
+
+	if !ok {
+		return form, false, nil
+	}
+
+	var exp, err = in.expandMacro(m, form.(*parser.ConsCell))
+
+	return exp, err == nil, err
+} // func (in *Interpreter) macroexpand1(form parser.LispValue) (parser.LispValue, bool, error)
+
+// macroexpand expands form repeatedly until it is no longer a macro call.
+func (in *Interpreter) macroexpand(form parser.LispValue) (parser.LispValue, error) {
+	for {
+		var (
+			err      error
+			expanded bool
+		)
+
+		if form, expanded, err = in.macroexpand1(form); err != nil {
+			return nil, err
+		} else if !expanded {
+			return form, nil
+		}
+	}
+} // func (in *Interpreter) macroexpand(form parser.LispValue) (parser.LispValue, error)
+
+// macroexpandFunction implements MACROEXPAND and MACROEXPAND-1.
+func (in *Interpreter) macroexpandFunction(form string, args []parser.LispValue) (parser.LispValue, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("Wrong number of arguments to %s: %d (expected 1)",
+			form,
+			len(args))
+	} else if form == "MACROEXPAND" {
+		return in.macroexpand(args[0])
+	}
+
+	var exp, _, err = in.macroexpand1(args[0])
+
+	return exp, err
+} // func (in *Interpreter) macroexpandFunction(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// gensymFunction implements GENSYM, which takes an optional String to use
+// as the prefix of the name instead of G.
+func (in *Interpreter) gensymFunction(form string, args []parser.LispValue) (parser.LispValue, error) {
+	var prefix = "G"
+
+	if len(args) > 1 {
+		return nil, fmt.Errorf("Wrong number of arguments to %s: %d (expected 0 or 1)",
+			form,
+			len(args))
+	} else if len(args) == 1 {
+		var s, ok = args[0].(parser.String)
+
+		if !ok {
+			return nil, fmt.Errorf("%w: Argument to %s must be a String, not a %s",
+				ErrType,
+				form,
+				args[0].Type())
+		}
+
+		prefix = s.Str
+	}
+
+	return in.gensym(prefix), nil
+} // func (in *Interpreter) gensymFunction(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// gensym returns a fresh, uninterned Symbol. It cannot clash with any other
+// Symbol, even one of the same name, and the #: prefix shows as much when
+// it is printed.
+func (in *Interpreter) gensym(prefix string) parser.Symbol {
+	in.GensymCounter++
+
+	return parser.MakeUninterned(fmt.Sprintf("#:%s%d", prefix, in.GensymCounter))
+} // func (in *Interpreter) gensym(prefix string) parser.Symbol
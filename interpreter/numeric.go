@@ -0,0 +1,324 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/numeric.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 17. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-17 19:02:17 krylon>
+
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// Arithmetic follows the usual contagion rule: As long as all operands are
+// Integers, the result is an Integer. As soon as one operand is a Float, the
+// other one is converted to Float as well, and so is the result.
+// Integer arithmetic that overflows an int64 is an error rather than
+// wrapping around. Likewise, Float arithmetic whose result is not a finite
+// number, i.e. infinite or NaN, is an error, since those have no printed
+// representation the reader accepts. Dividing Integers yields an Integer if
+// the division is exact, and a Float otherwise.
+
+// ErrArithmetic indicates an arithmetic operation whose result cannot be
+// represented, such as an Integer overflow.
+var ErrArithmetic = errors.New("Arithmetic error")
+
+// ErrDivisionByZero indicates a division by zero.
+var ErrDivisionByZero = errors.New("Division by zero")
+
+func isNumber(v parser.LispValue) bool {
+	switch v.(type) {
+	case parser.Integer, parser.Float:
+		return true
+	default:
+		return false
+	}
+} // func isNumber(v parser.LispValue) bool
+
+// toFloat returns the value of a number as float64.
+func toFloat(v parser.LispValue) float64 {
+	switch n := v.(type) {
+	case parser.Integer:
+		return float64(n.Int)
+	case parser.Float:
+		return n.Flt
+	default:
+		panic(fmt.Errorf("toFloat called with non-numeric value %s", v))
+	}
+} // func toFloat(v parser.LispValue) float64
+
+// checkNumbers returns an error if either argument is not a number.
+// op is the name of the operation, for the error message.
+func checkNumbers(op string, a, b parser.LispValue) error {
+	for _, v := range []parser.LispValue{a, b} {
+		if !isNumber(v) {
+			return fmt.Errorf("%w: Argument to %s must be a number, not a %s (%s)",
+				ErrType,
+				op,
+				v.Type(),
+				v)
+		}
+	}
+
+	return nil
+} // func checkNumbers(op string, a, b parser.LispValue) error
+
+// numOp is a binary arithmetic operation on two numbers.
+type numOp func(a, b parser.LispValue) (parser.LispValue, error)
+
+// integers returns the values of a and b if both are Integers.
+func integers(a, b parser.LispValue) (int64, int64, bool) {
+	var x, xok = a.(parser.Integer)
+	var y, yok = b.(parser.Integer)
+
+	return x.Int, y.Int, xok && yok
+} // func integers(a, b parser.LispValue) (int64, int64, bool)
+
+// overflow returns the error for an Integer operation that overflows.
+func overflow(x int64, op string, y int64) error {
+	return fmt.Errorf("%w: Integer overflow in %d %s %d",
+		ErrArithmetic,
+		x,
+		op,
+		y)
+} // func overflow(x int64, op string, y int64) error
+
+// floatResult returns r, the result of the Float operation x op y, as a
+// Float, or an error if it is not a finite number.
+func floatResult(x float64, op string, y, r float64) (parser.LispValue, error) {
+	if math.IsInf(r, 0) || math.IsNaN(r) {
+		return nil, fmt.Errorf("%w: %g %s %g has no finite result",
+			ErrArithmetic,
+			x,
+			op,
+			y)
+	}
+
+	return parser.Float{Flt: r}, nil
+} // func floatResult(x float64, op string, y, r float64) (parser.LispValue, error)
+
+func numAdd(a, b parser.LispValue) (parser.LispValue, error) {
+	if err := checkNumbers("+", a, b); err != nil {
+		return nil, err
+	} else if x, y, ok := integers(a, b); ok {
+		var r = x + y
+
+		if (x > 0 && y > 0 && r < 0) || (x < 0 && y < 0 && r >= 0) {
+			return nil, overflow(x, "+", y)
+		}
+
+		return parser.Integer{Int: r}, nil
+	}
+
+	var f1, f2 = toFloat(a), toFloat(b)
+
+	return floatResult(f1, "+", f2, f1+f2)
+} // func numAdd(a, b parser.LispValue) (parser.LispValue, error)
+
+func numSub(a, b parser.LispValue) (parser.LispValue, error) {
+	if err := checkNumbers("-", a, b); err != nil {
+		return nil, err
+	} else if x, y, ok := integers(a, b); ok {
+		var r = x - y
+
+		if (x >= 0 && y < 0 && r < 0) || (x < 0 && y > 0 && r >= 0) {
+			return nil, overflow(x, "-", y)
+		}
+
+		return parser.Integer{Int: r}, nil
+	}
+
+	var f1, f2 = toFloat(a), toFloat(b)
+
+	return floatResult(f1, "-", f2, f1-f2)
+} // func numSub(a, b parser.LispValue) (parser.LispValue, error)
+
+func numMul(a, b parser.LispValue) (parser.LispValue, error) {
+	if err := checkNumbers("*", a, b); err != nil {
+		return nil, err
+	} else if x, y, ok := integers(a, b); ok {
+		if x == 0 || y == 0 {
+			return parser.Integer{Int: 0}, nil
+		}
+
+		var r = x * y
+
+		if r/y != x || (x == -1 && y == math.MinInt64) || (y == -1 && x == math.MinInt64) {
+			return nil, overflow(x, "*", y)
+		}
+
+		return parser.Integer{Int: r}, nil
+	}
+
+	var f1, f2 = toFloat(a), toFloat(b)
+
+	return floatResult(f1, "*", f2, f1*f2)
+} // func numMul(a, b parser.LispValue) (parser.LispValue, error)
+
+func numDiv(a, b parser.LispValue) (parser.LispValue, error) {
+	if err := checkNumbers("/", a, b); err != nil {
+		return nil, err
+	} else if toFloat(b) == 0 {
+		return nil, fmt.Errorf("%w: %s / %s", ErrDivisionByZero, a, b)
+	} else if x, y, ok := integers(a, b); ok {
+		if x == math.MinInt64 && y == -1 {
+			return nil, overflow(x, "/", y)
+		} else if x%y == 0 {
+			return parser.Integer{Int: x / y}, nil
+		}
+	}
+
+	var f1, f2 = toFloat(a), toFloat(b)
+
+	return floatResult(f1, "/", f2, f1/f2)
+} // func numDiv(a, b parser.LispValue) (parser.LispValue, error)
+
+// numRem returns the remainder of dividing a by b, which has the sign of a.
+func numRem(a, b parser.LispValue) (parser.LispValue, error) {
+	if err := checkNumbers("%", a, b); err != nil {
+		return nil, err
+	} else if toFloat(b) == 0 {
+		return nil, fmt.Errorf("%w: %s %% %s", ErrDivisionByZero, a, b)
+	} else if x, y, ok := integers(a, b); ok {
+		return parser.Integer{Int: x % y}, nil
+	}
+
+	var f1, f2 = toFloat(a), toFloat(b)
+
+	return floatResult(f1, "%", f2, math.Mod(f1, f2))
+} // func numRem(a, b parser.LispValue) (parser.LispValue, error)
+
+// unordered is the result of numCompare for a pair of numbers that cannot
+// be ordered because one of them is NaN.
+const unordered = 2
+
+// numCompare compares two numbers, returning -1 if a < b, 0 if a == b,
+// and 1 if a > b. Integers are compared exactly, mixed comparisons are
+// done on float64. If either number is NaN, the result is unordered.
+func numCompare(op string, a, b parser.LispValue) (int, error) {
+	if err := checkNumbers(op, a, b); err != nil {
+		return 0, err
+	}
+
+	if x, y, ok := integers(a, b); ok {
+		switch {
+		case x < y:
+			return -1, nil
+		case x > y:
+			return 1, nil
+		default:
+			return 0, nil
+		}
+	}
+
+	var f1, f2 = toFloat(a), toFloat(b)
+
+	switch {
+	case f1 < f2:
+		return -1, nil
+	case f1 > f2:
+		return 1, nil
+	case f1 == f2:
+		return 0, nil
+	default:
+		return unordered, nil
+	}
+} // func numCompare(op string, a, b parser.LispValue) (int, error)
+
+// foldOps are the operations and identities of the folding arithmetic
+// functions.
+var foldOps = map[string]struct {
+	op       numOp
+	identity int64
+}{
+	"+": {numAdd, 0},
+	"*": {numMul, 1},
+	"-": {numSub, 0},
+	"/": {numDiv, 1},
+}
+
+// numFold implements +, *, - and /, which fold their arguments with the
+// operation from left to right. + and * start with the identity of the
+// operation, so they accept any number of arguments. - and / need at
+// least one argument; given only one, they return its negation or
+// reciprocal, respectively.
+func numFold(form string, args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err error
+		f                    = foldOps[form]
+		acc parser.LispValue = parser.Integer{Int: f.identity}
+	)
+
+	if form == "-" || form == "/" {
+		if len(args) == 0 {
+			return nil, fmt.Errorf("Wrong number of arguments to %s: 0 (expect >= 1)",
+				form)
+		} else if len(args) > 1 {
+			acc, args = args[0], args[1:]
+		}
+	}
+
+	for _, arg := range args {
+		if acc, err = f.op(acc, arg); err != nil {
+			return nil, err
+		}
+	}
+
+	return acc, nil
+} // func numFold(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// numRemainder implements %, the remainder of dividing exactly two numbers.
+func numRemainder(form string, args []parser.LispValue) (parser.LispValue, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("Wrong number of arguments to %s: %d (expect 2)",
+			form,
+			len(args))
+	}
+
+	return numRem(args[0], args[1])
+} // func numRemainder(form string, args []parser.LispValue) (parser.LispValue, error)
+
+// comparisons map the comparison functions to the results of numCompare
+// that make them true for a pair of arguments. None of them holds for
+// unordered arguments.
+var comparisons = map[string]func(cmp int) bool{
+	"<":  func(cmp int) bool { return cmp == -1 },
+	">":  func(cmp int) bool { return cmp == 1 },
+	"=":  func(cmp int) bool { return cmp == 0 },
+	"<=": func(cmp int) bool { return cmp == -1 || cmp == 0 },
+	">=": func(cmp int) bool { return cmp == 0 || cmp == 1 },
+}
+
+// numCompareAll implements <, >, =, <= and >=, which return T if the
+// comparison holds for each pair of adjacent arguments, e.g. if the
+// arguments of < are in strictly increasing order.
+func numCompareAll(form string, args []parser.LispValue) (parser.LispValue, error) {
+	var holds = comparisons[form]
+
+	if len(args) == 0 {
+		return nil, fmt.Errorf("Wrong number of arguments to %s: 0 (expect >= 1)",
+			form)
+	} else if len(args) == 1 {
+		if err := checkNumbers(form, args[0], args[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	var res = sym("t")
+
+	// All arguments are checked for being numbers, even after the result
+	// is known.
+	for i := 1; i < len(args); i++ {
+		if cmp, err := numCompare(form, args[i-1], args[i]); err != nil {
+			return nil, err
+		} else if !holds(cmp) {
+			res = sym("nil")
+		}
+	}
+
+	return res, nil
+} // func numCompareAll(form string, args []parser.LispValue) (parser.LispValue, error)
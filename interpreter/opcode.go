@@ -0,0 +1,41 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/opcode.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 09:12:40 krylon>
+
+package interpreter
+
+//go:generate stringer -type=Opcode
+
+// Opcode identifies an instruction of the virtual machine.
+type Opcode uint8
+
+// The VM is a stack machine. Unless noted otherwise, instructions pop their
+// operands off the stack and push their result.
+const (
+	OpConst          Opcode = iota // Push constant a
+	OpLocal                        // Push local variable a
+	OpSetLocal                     // Pop a value and store it in local variable a
+	OpUpval                        // Push captured variable a
+	OpGlobal                       // Look up the Symbol in constant a
+	OpFunction                     // Look up the function named by the Symbol in constant a
+	OpPop                          // Discard the top of the stack
+	OpJump                         // Continue at a
+	OpJumpIfFalse                  // Pop a value, continue at a if it is false
+	OpJumpIfSupplied               // Continue at b if optional argument a was passed
+	OpSupplied                     // Push T if optional argument a was passed, NIL otherwise
+	OpClosure                      // Push a Closure for prototype a
+	OpDefun                        // Bind the Closure on the stack to the Symbol in constant a
+	OpPrim                         // Apply the primitive named by constant a to b arguments
+	OpCall                         // Call a function with a arguments
+	OpTailCall                     // Like OpCall, but reuses the current frame
+	OpReturn                       // Return the top of the stack to the caller
+)
+
+// instr is a single instruction. The meaning of the operands depends on
+// the Opcode.
+type instr struct {
+	op   Opcode
+	a, b int32
+}
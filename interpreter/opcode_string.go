@@ -0,0 +1,40 @@
+// Code generated by "stringer -type=Opcode"; DO NOT EDIT.
+
+package interpreter
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[OpConst-0]
+	_ = x[OpLocal-1]
+	_ = x[OpSetLocal-2]
+	_ = x[OpUpval-3]
+	_ = x[OpGlobal-4]
+	_ = x[OpFunction-5]
+	_ = x[OpPop-6]
+	_ = x[OpJump-7]
+	_ = x[OpJumpIfFalse-8]
+	_ = x[OpJumpIfSupplied-9]
+	_ = x[OpSupplied-10]
+	_ = x[OpClosure-11]
+	_ = x[OpDefun-12]
+	_ = x[OpPrim-13]
+	_ = x[OpCall-14]
+	_ = x[OpTailCall-15]
+	_ = x[OpReturn-16]
+}
+
+const _Opcode_name = "OpConstOpLocalOpSetLocalOpUpvalOpGlobalOpFunctionOpPopOpJumpOpJumpIfFalseOpJumpIfSuppliedOpSuppliedOpClosureOpDefunOpPrimOpCallOpTailCallOpReturn"
+
+var _Opcode_index = [...]uint8{0, 7, 14, 24, 31, 39, 49, 54, 60, 73, 89, 99, 108, 115, 121, 127, 137, 145}
+
+func (i Opcode) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_Opcode_index)-1 {
+		return "Opcode(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _Opcode_name[_Opcode_index[idx]:_Opcode_index[idx+1]]
+}
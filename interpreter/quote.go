@@ -0,0 +1,164 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/quote.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 17. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-17 21:05:33 krylon>
+
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// quoteForm returns the operand if v is a list of the form (name operand),
+// e.g. (UNQUOTE x).
+func quoteForm(v parser.LispValue, name string) (parser.LispValue, bool) {
+	var (
+		l  *parser.ConsCell
+		s  parser.Symbol
+		ok bool
+	)
+
+	if l, ok = v.(*parser.ConsCell); !ok || l.Length() != 2 {
+		return nil, false
+	} else if s, ok = l.Car.(parser.Symbol); !ok || s.Sym != name {
+		return nil, false
+	}
+
+	return l.Rest().Car, true
+} // func quoteForm(v parser.LispValue, name string) (parser.LispValue, bool)
+
+// isQuoteForm returns true if v is an UNQUOTE, UNQUOTE-SPLICING or
+// QUASIQUOTE form.
+func isQuoteForm(v parser.LispValue) bool {
+	for _, name := range []string{"UNQUOTE", "UNQUOTE-SPLICING", "QUASIQUOTE"} {
+		if _, ok := quoteForm(v, name); ok {
+			return true
+		}
+	}
+
+	return false
+} // func isQuoteForm(v parser.LispValue) bool
+
+// quasiquote expands the template v of a QUASIQUOTE form. depth is the
+// nesting level of QUASIQUOTE forms; only UNQUOTE and UNQUOTE-SPLICING
+// forms at level 1 are evaluated, deeper ones are copied with their
+// operand expanded one level down.
+func (in *Interpreter) quasiquote(v parser.LispValue, depth int) (parser.LispValue, error) {
+	var (
+		err   error
+		ok    bool
+		x     parser.LispValue
+		items []parser.LispValue
+	)
+
+	if x, ok = quoteForm(v, "UNQUOTE"); ok {
+		if depth == 1 {
+			return in.Eval(x)
+		} else if items, err = in.quasiquoteItems([]parser.LispValue{x}, depth-1); err != nil {
+			return nil, err
+		}
+
+		return list(append([]parser.LispValue{sym("unquote")}, items...)...), nil
+	} else if _, ok = quoteForm(v, "UNQUOTE-SPLICING"); ok && depth == 1 {
+		return nil, fmt.Errorf(",@ is only allowed within a list: %s", v)
+	} else if x, ok = quoteForm(v, "QUASIQUOTE"); ok {
+		if x, err = in.quasiquote(x, depth+1); err != nil {
+			return nil, err
+		}
+
+		return list(sym("quasiquote"), x), nil
+	}
+
+	var (
+		l    *parser.ConsCell
+		tail parser.LispValue
+	)
+
+	if l, ok = v.(*parser.ConsCell); !ok {
+		return v, nil
+	}
+
+	// Walk the cells of the template instead of its items, so dotted
+	// templates keep their tail. The reader turns (a . ,b) into
+	// (A UNQUOTE B), so a Cdr that is itself an UNQUOTE form is the
+	// unquoted tail of the list.
+	for c := l; ; {
+		var expanded []parser.LispValue
+
+		if expanded, err = in.quasiquoteItems([]parser.LispValue{c.Car}, depth); err != nil {
+			return nil, err
+		}
+
+		items = append(items, expanded...)
+
+		var next, isCell = c.Cdr.(*parser.ConsCell)
+
+		if !isCell {
+			tail = c.Tail()
+			break
+		} else if isQuoteForm(next) {
+			if tail, err = in.quasiquote(next, depth); err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		c = next
+	}
+
+	if err = in.alloc(len(items)); err != nil {
+		return nil, err
+	}
+
+	return parser.MakeDotted(tail, items...), nil
+} // func (in *Interpreter) quasiquote(v parser.LispValue, depth int) (parser.LispValue, error)
+
+// quasiquoteItems expands the elements of a list within a QUASIQUOTE
+// template. This is where UNQUOTE-SPLICING forms at level 1 are evaluated
+// and spliced into the surrounding list.
+func (in *Interpreter) quasiquoteItems(items []parser.LispValue, depth int) ([]parser.LispValue, error) {
+	var expanded = make([]parser.LispValue, 0, len(items))
+
+	for _, item := range items {
+		var (
+			err    error
+			ok     bool
+			x, val parser.LispValue
+			splice []parser.LispValue
+		)
+
+		if x, ok = quoteForm(item, "UNQUOTE-SPLICING"); !ok {
+			if val, err = in.quasiquote(item, depth); err != nil {
+				return nil, err
+			}
+
+			expanded = append(expanded, val)
+			continue
+		} else if depth > 1 {
+			if splice, err = in.quasiquoteItems([]parser.LispValue{x}, depth-1); err != nil {
+				return nil, err
+			}
+
+			splice = append([]parser.LispValue{sym("unquote-splicing")}, splice...)
+			expanded = append(expanded, list(splice...))
+			continue
+		}
+
+		if val, err = in.Eval(x); err != nil {
+			return nil, err
+		} else if splice, ok = listItems(val); !ok {
+			return nil, fmt.Errorf("%w: ,@%s must evaluate to a list, not a %s (%s)",
+				ErrType,
+				x,
+				val.Type(),
+				val)
+		}
+
+		expanded = append(expanded, splice...)
+	}
+
+	return expanded, nil
+} // func (in *Interpreter) quasiquoteItems(items []parser.LispValue, depth int) ([]parser.LispValue, error)
@@ -0,0 +1,280 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/sandbox.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 14:02:19 krylon>
+
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// Builtins that interact with the operating system require capabilities.
+// An Interpreter created with MakeSandbox gets the capabilities of the
+// profile it is created with. Builtins whose capabilities the profile does
+// not grant are still bound, but calling them fails with ErrSecurity, which
+// Lisp code sees as a SECURITY-ERROR.
+//
+// Lisp code has no way to acquire capabilities: The profile is fixed when
+// the Interpreter is created, and the Environment is always a fresh one, so
+// no builtins of a more privileged Interpreter can leak into it.
+
+// ErrSecurity indicates an attempt to use a capability the Interpreter's
+// profile does not grant.
+var ErrSecurity = errors.New("Security violation")
+
+// Capability is a set of privileges builtins may require.
+type Capability uint8
+
+// These are the capabilities builtins may require.
+const (
+	CapReadFS Capability = 1 << iota
+	CapWriteFS
+	CapEnv
+	CapExec
+	CapNone Capability = 0
+	CapAll             = CapReadFS | CapWriteFS | CapEnv | CapExec
+)
+
+var capNames = []string{"read-fs", "write-fs", "env", "exec"}
+
+func (c Capability) String() string {
+	var names []string
+
+	for i, name := range capNames {
+		if c&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, ",")
+} // func (c Capability) String() string
+
+// profiles maps the names of the capability profiles to the capabilities
+// they grant.
+var profiles = map[string]Capability{
+	"pure":         CapNone,
+	"read-only-fs": CapReadFS,
+	"full":         CapAll,
+}
+
+// Profiles returns the names of the capability profiles, sorted.
+func Profiles() []string {
+	var names = make([]string, 0, len(profiles))
+
+	for name := range profiles {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+} // func Profiles() []string
+
+// sysBuiltin is a builtin that requires capabilities.
+type sysBuiltin struct {
+	name string
+	caps Capability
+	fn   BuiltinFunc
+}
+
+var sysBuiltins = []sysBuiltin{
+	{"read-file", CapReadFS, sysReadFile},
+	{"file-exists-p", CapReadFS, sysFileExists},
+	{"directory", CapReadFS, sysDirectory},
+	{"write-file", CapWriteFS, sysWriteFile},
+	{"delete-file", CapWriteFS, sysDeleteFile},
+	{"getenv", CapEnv, sysGetenv},
+	{"run-program", CapExec, sysRunProgram},
+}
+
+// MakeSandbox creates an Interpreter with a fresh Environment, with the
+// capabilities of the named profile, see Profiles.
+func MakeSandbox(profile string, dbg bool) (*Interpreter, error) {
+	var (
+		err  error
+		in   *Interpreter
+		caps Capability
+		ok   bool
+	)
+
+	if caps, ok = profiles[profile]; !ok {
+		return nil, fmt.Errorf("Unknown capability profile %q (expected one of %s)",
+			profile,
+			strings.Join(Profiles(), ", "))
+	} else if in, err = MakeInterpreter(nil, dbg); err != nil {
+		return nil, err
+	}
+
+	in.profile = profile
+
+	for _, b := range sysBuiltins {
+		if b.caps&caps == b.caps {
+			in.Env.Define(b.name, NewBuiltin(b.name, b.fn))
+		} else {
+			in.Env.Define(b.name, NewBuiltin(b.name, denied(profile, b)))
+		}
+	}
+
+	return in, nil
+} // func MakeSandbox(profile string, dbg bool) (*Interpreter, error)
+
+// Profile returns the name of the capability profile the Interpreter was
+// created with, or an empty string if it was not created by MakeSandbox.
+func (in *Interpreter) Profile() string {
+	return in.profile
+} // func (in *Interpreter) Profile() string
+
+// denied returns the function a builtin is bound to when the profile does
+// not grant the capabilities it requires.
+func denied(profile string, b sysBuiltin) BuiltinFunc {
+	return func([]parser.LispValue) (parser.LispValue, error) {
+		return nil, fmt.Errorf("%w: %s requires capability %s, which profile %s does not grant",
+			ErrSecurity,
+			strings.ToUpper(b.name),
+			b.caps,
+			profile)
+	}
+} // func denied(profile string, b sysBuiltin) BuiltinFunc
+
+// stringArgs checks that args consists of at least min and at most max
+// Strings, and returns their values.
+func stringArgs(args []parser.LispValue, min, max int) ([]string, error) {
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		return nil, fmt.Errorf("Wrong number of arguments: %d", len(args))
+	}
+
+	var strs = make([]string, len(args))
+
+	for i, a := range args {
+		var s, ok = a.(parser.String)
+
+		if !ok {
+			return nil, fmt.Errorf("%w: Argument %d must be a String, not a %s (%s)",
+				ErrType,
+				i+1,
+				a.Type(),
+				a)
+		}
+
+		strs[i] = s.Str
+	}
+
+	return strs, nil
+} // func stringArgs(args []parser.LispValue, min, max int) ([]string, error)
+
+func sysReadFile(args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err     error
+		strs    []string
+		content []byte
+	)
+
+	if strs, err = stringArgs(args, 1, 1); err != nil {
+		return nil, err
+	} else if content, err = os.ReadFile(strs[0]); err != nil {
+		return nil, err
+	}
+
+	return parser.MakeString(string(content)), nil
+} // func sysReadFile(args []parser.LispValue) (parser.LispValue, error)
+
+func sysFileExists(args []parser.LispValue) (parser.LispValue, error) {
+	var strs, err = stringArgs(args, 1, 1)
+
+	if err != nil {
+		return nil, err
+	} else if _, err = os.Stat(strs[0]); err != nil {
+		return sym("nil"), nil
+	}
+
+	return sym("t"), nil
+} // func sysFileExists(args []parser.LispValue) (parser.LispValue, error)
+
+func sysDirectory(args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err     error
+		strs    []string
+		entries []os.DirEntry
+	)
+
+	if strs, err = stringArgs(args, 1, 1); err != nil {
+		return nil, err
+	} else if entries, err = os.ReadDir(strs[0]); err != nil {
+		return nil, err
+	}
+
+	var names = make([]parser.LispValue, len(entries))
+
+	for i, e := range entries {
+		names[i] = parser.MakeString(e.Name())
+	}
+
+	return list(names...), nil
+} // func sysDirectory(args []parser.LispValue) (parser.LispValue, error)
+
+func sysWriteFile(args []parser.LispValue) (parser.LispValue, error) {
+	var strs, err = stringArgs(args, 2, 2)
+
+	if err != nil {
+		return nil, err
+	} else if err = os.WriteFile(strs[0], []byte(strs[1]), 0644); err != nil {
+		return nil, err
+	}
+
+	return sym("t"), nil
+} // func sysWriteFile(args []parser.LispValue) (parser.LispValue, error)
+
+func sysDeleteFile(args []parser.LispValue) (parser.LispValue, error) {
+	var strs, err = stringArgs(args, 1, 1)
+
+	if err != nil {
+		return nil, err
+	} else if err = os.Remove(strs[0]); err != nil {
+		return nil, err
+	}
+
+	return sym("t"), nil
+} // func sysDeleteFile(args []parser.LispValue) (parser.LispValue, error)
+
+func sysGetenv(args []parser.LispValue) (parser.LispValue, error) {
+	var strs, err = stringArgs(args, 1, 1)
+
+	if err != nil {
+		return nil, err
+	} else if val, ok := os.LookupEnv(strs[0]); ok {
+		return parser.MakeString(val), nil
+	}
+
+	return sym("nil"), nil
+} // func sysGetenv(args []parser.LispValue) (parser.LispValue, error)
+
+// sysRunProgram runs a program with the given arguments, without a shell,
+// and returns its standard output.
+func sysRunProgram(args []parser.LispValue) (parser.LispValue, error) {
+	var (
+		err  error
+		strs []string
+		out  []byte
+	)
+
+	if strs, err = stringArgs(args, 1, -1); err != nil {
+		return nil, err
+	} else if out, err = exec.Command(strs[0], strs[1:]...).Output(); err != nil {
+		return nil, err
+	}
+
+	return parser.MakeString(string(out)), nil
+} // func sysRunProgram(args []parser.LispValue) (parser.LispValue, error)
@@ -0,0 +1,126 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/values.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 17. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-17 10:12:44 krylon>
+
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// A form can return more than one value, like in Common Lisp. The first,
+// primary value is what Eval returns, any further values are kept in the
+// Interpreter until the next form is evaluated. Only these forms look at
+// them, everywhere else just the primary value is used:
+//
+//	(values vals...)                             ; returns all of vals
+//	(multiple-value-list form)                   ; => List of all values
+//	(multiple-value-bind (vars...) form body...) ; binds vars to the values
+//
+// Secondary values survive as long as a form's value is returned in tail
+// position, e.g. from the last form of a function body. GETHASH returns
+// whether the key was found as its second value.
+
+// multipleValues returns the primary value of vals, or NIL if there are
+// none, and keeps the rest for MULTIPLE-VALUE-LIST and
+// MULTIPLE-VALUE-BIND.
+func (in *Interpreter) multipleValues(vals ...parser.LispValue) parser.LispValue {
+	if len(vals) == 0 {
+		in.values = nil
+		return sym("nil")
+	}
+
+	in.values = vals[1:]
+
+	return vals[0]
+} // func (in *Interpreter) multipleValues(vals ...parser.LispValue) parser.LispValue
+
+// valuesFunction implements VALUES.
+func (in *Interpreter) valuesFunction(_ string, args []parser.LispValue) (parser.LispValue, error) {
+	return in.multipleValues(args...), nil
+} // func (in *Interpreter) valuesFunction(_ string, args []parser.LispValue) (parser.LispValue, error)
+
+// allValues evaluates form and returns all of its values.
+func (in *Interpreter) allValues(form parser.LispValue) ([]parser.LispValue, error) {
+	var val, err = in.Eval(form)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var vals = append([]parser.LispValue{val}, in.values...)
+
+	in.values = nil
+
+	return vals, nil
+} // func (in *Interpreter) allValues(form parser.LispValue) ([]parser.LispValue, error)
+
+// multipleValueList implements MULTIPLE-VALUE-LIST.
+func (in *Interpreter) multipleValueList(l *parser.ConsCell) (parser.LispValue, error) {
+	var (
+		err  error
+		vals []parser.LispValue
+	)
+
+	if cnt := l.Length(); cnt != 2 {
+		return nil, fmt.Errorf("Wrong number of arguments to MULTIPLE-VALUE-LIST: %d (expected 1)",
+			cnt-1)
+	} else if vals, err = in.allValues(l.Rest().Car); err != nil {
+		return nil, err
+	}
+
+	return in.freshList(vals)
+} // func (in *Interpreter) multipleValueList(l *parser.ConsCell) (parser.LispValue, error)
+
+// reduceMultipleValueBind evaluates the value form of a MULTIPLE-VALUE-BIND
+// and returns a LET that binds the variables to its values, for Eval to
+// evaluate in tail position. Variables without a value are bound to NIL,
+// values without a variable are ignored.
+func (in *Interpreter) reduceMultipleValueBind(l *parser.ConsCell) (parser.LispValue, error) {
+	var (
+		err      error
+		ok       bool
+		vars     []parser.LispValue
+		vals     []parser.LispValue
+		bindings []parser.LispValue
+	)
+
+	if l.Length() < 3 {
+		return nil, fmt.Errorf("MULTIPLE-VALUE-BIND needs a list of variables and a value form")
+	} else if vars, ok = listItems(l.Rest().Car); !ok {
+		return nil, fmt.Errorf("Variables of MULTIPLE-VALUE-BIND must be a List, not %s",
+			l.Rest().Car)
+	}
+
+	for _, v := range vars {
+		if _, err = variableName("MULTIPLE-VALUE-BIND", v); err != nil {
+			return nil, err
+		}
+	}
+
+	if vals, err = in.allValues(l.Rest().Rest().Car); err != nil {
+		return nil, err
+	}
+
+	bindings = make([]parser.LispValue, len(vars))
+
+	for i, v := range vars {
+		var val parser.LispValue = sym("nil")
+
+		if i < len(vals) {
+			val = vals[i]
+		}
+
+		bindings[i] = list(v, quoted(val))
+	}
+
+	if err = in.alloc(3*len(bindings) + 2); err != nil {
+		return nil, err
+	}
+
+	return parser.Cons(sym("let"), parser.Cons(list(bindings...), l.Rest().Rest().Tail())), nil
+} // func (in *Interpreter) reduceMultipleValueBind(l *parser.ConsCell) (parser.LispValue, error)
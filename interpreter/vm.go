@@ -0,0 +1,491 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/interpreter/vm.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 10:41:55 krylon>
+
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/blicero/krylisp/parser"
+	"github.com/blicero/krylisp/types"
+)
+
+// Closure is a compiled function, along with the values of the variables
+// it captured from the functions enclosing it. To Lisp code, it looks just
+// like a Function.
+//
+// epoch is the epoch of the Interpreter the Closure was last checked for
+// outdated code at, stale the result of that check.
+type Closure struct {
+	proto  *proto
+	upvals []parser.LispValue
+	env    *scope
+	epoch  uint64
+	stale  bool
+}
+
+func (cl *Closure) String() string {
+	return lambdaString(cl.proto.argList, cl.proto.body)
+} // func (cl *Closure) String() string
+
+// Type returns the type of the receiver, i.e. types.Function
+func (cl *Closure) Type() types.Type { return types.Function }
+
+// Equal compares the receiver to another LispValue for equality.
+// Closures are only equal to themselves.
+func (cl *Closure) Equal(other parser.LispValue) bool {
+	var o, ok = other.(*Closure)
+
+	return ok && o == cl
+} // func (cl *Closure) Equal(other parser.LispValue) bool
+
+// displayName returns the name of the Closure for use in messages.
+func (cl *Closure) displayName() string {
+	if cl.proto.name == "" {
+		return "anonymous function"
+	}
+
+	return cl.proto.name
+} // func (cl *Closure) displayName() string
+
+// outdated returns true if the code of cl no longer means what its source
+// does, because a function it calls by name has become a macro, or one of
+// its parameters has become a special variable since it was compiled.
+func (cl *Closure) outdated(in *Interpreter) bool {
+	if cl.epoch == in.epoch {
+		return cl.stale
+	}
+
+	var ll = cl.proto.params
+
+	cl.epoch = in.epoch
+
+	for _, s := range cl.proto.calls {
+		if val, ok := cl.env.lookup(s.Atom()); ok {
+			if _, isMacro := val.(*Macro); isMacro {
+				cl.stale = true
+				return true
+			}
+		}
+	}
+
+	for _, s := range ll.required {
+		if in.isSpecialVar(s) {
+			cl.stale = true
+			return true
+		}
+	}
+
+	for _, p := range ll.optional {
+		if in.isSpecialVar(p.name) || (p.supplied != nil && in.isSpecialVar(*p.supplied)) {
+			cl.stale = true
+			return true
+		}
+	}
+
+	cl.stale = ll.rest != nil && in.isSpecialVar(*ll.rest)
+
+	return cl.stale
+} // func (cl *Closure) outdated(in *Interpreter) bool
+
+// interpret calls cl by having the tree-walker evaluate its source. The
+// variables the Closure captured are bound in a scope of their own, between
+// the scope it was created in and that of its parameters.
+func (in *Interpreter) interpret(cl *Closure, args []parser.LispValue) (parser.LispValue, error) {
+	var env = cl.env
+
+	if len(cl.upvals) > 0 {
+		env = &scope{
+			bindings: make(map[*parser.Atom]parser.LispValue, len(cl.upvals)),
+			parent:   cl.env,
+		}
+
+		for i, c := range cl.proto.captures {
+			env.bindings[c.name] = cl.upvals[i]
+		}
+	}
+
+	var fn = &Function{
+		name:    cl.proto.name,
+		argList: cl.proto.argList,
+		params:  cl.proto.params,
+		body:    cl.proto.body,
+		env:     env,
+	}
+
+	return in.apply(fn, args)
+} // func (in *Interpreter) interpret(cl *Closure, args []parser.LispValue) (parser.LispValue, error)
+
+// frame is the activation record of a call to a Closure. The local
+// variables of the call start at index base of the stack, the Closure
+// itself is right below them.
+type frame struct {
+	cl    *Closure
+	pc    int
+	base  int
+	nargs int
+}
+
+type vm struct {
+	in     *Interpreter
+	stack  []parser.LispValue
+	frames []frame
+}
+
+// Run executes compiled Code in the current scope of the Interpreter's
+// Environment.
+func (in *Interpreter) Run(code *Code) (parser.LispValue, error) {
+	var cl = &Closure{
+		proto: code.main,
+		env:   in.Env.scope,
+		epoch: code.main.epoch,
+	}
+
+	return in.execute(cl, nil)
+} // func (in *Interpreter) Run(code *Code) (parser.LispValue, error)
+
+// EvalCompiled compiles v and runs the resulting Code. If v cannot be
+// compiled, it is evaluated by Eval instead.
+func (in *Interpreter) EvalCompiled(v parser.LispValue) (parser.LispValue, error) {
+	var code, err = in.Compile(v)
+
+	if errors.Is(err, ErrNotCompilable) {
+		return in.Eval(v)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return in.Run(code)
+} // func (in *Interpreter) EvalCompiled(v parser.LispValue) (parser.LispValue, error)
+
+// execute calls the Closure cl with the given, already evaluated, arguments.
+func (in *Interpreter) execute(cl *Closure, args []parser.LispValue) (parser.LispValue, error) {
+	if cl.outdated(in) {
+		return in.interpret(cl, args)
+	}
+
+	var (
+		err error
+		fr  frame
+		m   = &vm{
+			in:    in,
+			stack: make([]parser.LispValue, 0, 64),
+		}
+	)
+
+	m.stack = append(m.stack, cl)
+	m.stack = append(m.stack, args...)
+
+	defer m.unwind()
+
+	if fr, err = m.bind(cl, 0, len(args)); err != nil {
+		return nil, err
+	} else if err = m.pushFrame(fr); err != nil {
+		return nil, err
+	}
+
+	return m.run()
+} // func (in *Interpreter) execute(cl *Closure, args []parser.LispValue) (parser.LispValue, error)
+
+func (m *vm) push(v parser.LispValue) {
+	m.stack = append(m.stack, v)
+} // func (m *vm) push(v parser.LispValue)
+
+func (m *vm) pop() parser.LispValue {
+	var v = m.stack[len(m.stack)-1]
+
+	m.stack = m.stack[:len(m.stack)-1]
+
+	return v
+} // func (m *vm) pop() parser.LispValue
+
+// popN removes the top n values from the stack and returns a copy of them.
+func (m *vm) popN(n int) []parser.LispValue {
+	var vals = make([]parser.LispValue, n)
+
+	copy(vals, m.stack[len(m.stack)-n:])
+	m.stack = m.stack[:len(m.stack)-n]
+
+	return vals
+} // func (m *vm) popN(n int) []parser.LispValue
+
+// pushFrame makes fr the current frame, which counts against the depth
+// limit of the evaluation.
+func (m *vm) pushFrame(fr frame) error {
+	if err := m.in.enter(); err != nil {
+		return err
+	}
+
+	m.frames = append(m.frames, fr)
+	return nil
+} // func (m *vm) pushFrame(fr frame) error
+
+// popFrame removes the current frame.
+func (m *vm) popFrame() {
+	m.frames = m.frames[:len(m.frames)-1]
+	m.in.leave()
+} // func (m *vm) popFrame()
+
+// unwind removes all frames that are left when the VM stops because of an
+// error.
+func (m *vm) unwind() {
+	for len(m.frames) > 0 {
+		m.popFrame()
+	}
+} // func (m *vm) unwind()
+
+// bind prepares a call to cl with the n arguments above index fnIdx of the
+// stack, where cl itself is. It collects surplus arguments into the &REST
+// list, and makes room for the remaining local variables.
+func (m *vm) bind(cl *Closure, fnIdx, n int) (frame, error) {
+	var (
+		ll   = cl.proto.params
+		base = fnIdx + 1
+		npos = len(ll.required) + len(ll.optional)
+	)
+
+	if n < ll.minArgs() || (ll.maxArgs() != -1 && n > ll.maxArgs()) {
+		return frame{}, fmt.Errorf("Incorrect number of arguments in call to %s: want %s, got %d",
+			cl.displayName(),
+			ll.arity(),
+			n)
+	}
+
+	if ll.rest != nil {
+		var rest = list()
+
+		if n > npos {
+			if err := m.in.alloc(n - npos); err != nil {
+				return frame{}, err
+			}
+
+			rest = list(m.stack[base+npos:]...)
+			m.stack = m.stack[:base+npos]
+		}
+
+		for len(m.stack) < base+npos {
+			m.push(nil)
+		}
+
+		m.push(rest)
+	}
+
+	for len(m.stack) < base+cl.proto.nlocals {
+		m.push(nil)
+	}
+
+	return frame{cl: cl, base: base, nargs: n}, nil
+} // func (m *vm) bind(cl *Closure, fnIdx, n int) (frame, error)
+
+// run executes instructions until the outermost frame returns.
+func (m *vm) run() (parser.LispValue, error) {
+	for {
+		var (
+			err error
+			f   = &m.frames[len(m.frames)-1]
+			p   = f.cl.proto
+			ins = p.code[f.pc]
+		)
+
+		f.pc++
+
+		if err = m.in.step(); err != nil {
+			return nil, err
+		}
+
+		switch ins.op {
+		case OpConst:
+			m.push(p.consts[ins.a])
+		case OpLocal:
+			m.push(m.stack[f.base+int(ins.a)])
+		case OpSetLocal:
+			m.stack[f.base+int(ins.a)] = m.pop()
+		case OpUpval:
+			m.push(f.cl.upvals[ins.a])
+		case OpGlobal:
+			var s = p.consts[ins.a].(parser.Symbol)
+
+			if val, ok := f.cl.env.lookup(s.Atom()); ok {
+				m.push(val)
+			} else {
+				return nil, fmt.Errorf("%w: %s", ErrUnbound, s)
+			}
+		case OpFunction:
+			var fn parser.LispValue
+
+			if fn, err = m.function(f.cl.env, p.consts[ins.a].(parser.Symbol)); err != nil {
+				return nil, err
+			}
+
+			m.push(fn)
+		case OpPop:
+			m.pop()
+		case OpJump:
+			f.pc = int(ins.a)
+		case OpJumpIfFalse:
+			if !asBool(m.pop()) {
+				f.pc = int(ins.a)
+			}
+		case OpJumpIfSupplied:
+			if f.nargs > len(p.params.required)+int(ins.a) {
+				f.pc = int(ins.b)
+			}
+		case OpSupplied:
+			if f.nargs > len(p.params.required)+int(ins.a) {
+				m.push(sym("t"))
+			} else {
+				m.push(sym("nil"))
+			}
+		case OpClosure:
+			var (
+				sub = p.protos[ins.a]
+				cl  = &Closure{
+					proto:  sub,
+					upvals: make([]parser.LispValue, len(sub.captures)),
+					env:    f.cl.env,
+					epoch:  sub.epoch,
+				}
+			)
+
+			for i, c := range sub.captures {
+				if c.local {
+					cl.upvals[i] = m.stack[f.base+c.idx]
+				} else {
+					cl.upvals[i] = f.cl.upvals[c.idx]
+				}
+			}
+
+			m.push(cl)
+		case OpDefun:
+			var name = p.consts[ins.a].(parser.Symbol)
+
+			m.in.Env.SetGlobal(name, m.pop())
+			m.push(name)
+		case OpPrim:
+			var (
+				res  parser.LispValue
+				form = p.consts[ins.a].(parser.Symbol).Sym
+			)
+
+			if res, err = primitives[form](form, m.popN(int(ins.b))); err != nil {
+				return nil, err
+			}
+
+			m.push(res)
+		case OpCall, OpTailCall:
+			if err = m.call(int(ins.a), ins.op == OpTailCall); err != nil {
+				return nil, err
+			}
+		case OpReturn:
+			var res = m.pop()
+
+			m.stack = m.stack[:f.base-1]
+			m.popFrame()
+
+			if len(m.frames) == 0 {
+				return res, nil
+			}
+
+			m.push(res)
+		default:
+			return nil, fmt.Errorf("Invalid opcode %s at %s:%d",
+				ins.op,
+				p.name,
+				f.pc-1)
+		}
+	}
+} // func (m *vm) run() (parser.LispValue, error)
+
+// function looks up the function a Symbol in the head of a call refers to.
+func (m *vm) function(env *scope, s parser.Symbol) (parser.LispValue, error) {
+	var val, ok = env.lookup(s.Atom())
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUndefined, s)
+	}
+
+	switch val.(type) {
+	case *Function, *Builtin, *Closure:
+		return val, nil
+	case *Macro:
+		return nil, fmt.Errorf("%w: %s became a macro while a call to it was running",
+			ErrEval,
+			s)
+	default:
+		return nil, fmt.Errorf("Type error: Binding for %s is not a function, but a %s (%s)",
+			s,
+			val.Type(),
+			val)
+	}
+} // func (m *vm) function(env *scope, s parser.Symbol) (parser.LispValue, error)
+
+// call calls the function on the stack below the top n values, which are
+// its arguments. A call to a Closure pushes a new frame, or, if tail is
+// true, replaces the current one. Functions, Builtins and outdated
+// Closures are called right away, and their result is pushed.
+func (m *vm) call(n int, tail bool) error {
+	var (
+		err   error
+		fnIdx = len(m.stack) - n - 1
+	)
+
+	switch fn := m.stack[fnIdx].(type) {
+	case *Closure:
+		var fr frame
+
+		if fn.outdated(m.in) {
+			return m.callOut(fn, n)
+		}
+
+		if !tail {
+			if fr, err = m.bind(fn, fnIdx, n); err != nil {
+				return err
+			}
+
+			return m.pushFrame(fr)
+		}
+
+		var base = m.frames[len(m.frames)-1].base - 1
+
+		copy(m.stack[base:], m.stack[fnIdx:])
+		m.stack = m.stack[:base+n+1]
+
+		if fr, err = m.bind(fn, base, n); err != nil {
+			return err
+		}
+
+		m.frames[len(m.frames)-1] = fr
+	case *Function, *Builtin:
+		return m.callOut(fn, n)
+	default:
+		return fmt.Errorf("Type error: Cannot call a %s (%s), it is not a function",
+			fn.Type(),
+			fn)
+	}
+
+	return nil
+} // func (m *vm) call(n int, tail bool) error
+
+// callOut calls fn, which the VM does not execute itself, with the top n
+// values of the stack as arguments, and replaces them and fn with the
+// result.
+func (m *vm) callOut(fn parser.LispValue, n int) error {
+	var (
+		err  error
+		res  parser.LispValue
+		args = m.popN(n)
+	)
+
+	m.pop()
+
+	if res, err = m.in.funcall(fn, args); err != nil {
+		return err
+	}
+
+	m.push(res)
+	return nil
+} // func (m *vm) callOut(fn parser.LispValue, n int) error
@@ -17,13 +17,7 @@ var par *participle.Parser[LispValue]
 func TestCreateParser(t *testing.T) {
 	var err error
 
-	if par, err = participle.Build[LispValue](
-		participle.Lexer(lex),
-		participle.Unquote("String"),
-		participle.Elide("Blank"),
-		participle.Upper("Symbol"),
-		participle.Union[LispValue](Symbol{}, Integer{}, String{}, List{}),
-	); err != nil {
+	if par, err = participle.Build[LispValue](options()...); err != nil {
 		par = nil
 		t.Fatalf("Failed to create Parser: %s", err.Error())
 	} else if par == nil {
@@ -47,13 +41,29 @@ func TestParse(t *testing.T) {
 		{filename: "symbol", expr: "T"},
 		{filename: "integer", expr: "42"},
 		{filename: "string", expr: `"Wer das liest, ist doof."`},
+		{filename: "string_escape", expr: `"Sie sagte \"Hallo\"\n"`},
+		{filename: "string_bad_escape", expr: `"C:\Pfad"`, expectError: true},
 		{filename: "list", expr: `(zebu alpha 23 69 "Hulululu")`},
 		{filename: "nested_list", expr: `(kappa gamma (lambda phi) 93 181 "Sapperlot!")`},
 		{filename: "arithmetic101", expr: `(+ 23 42)`},
 		{filename: "keyword", expr: `:value`},
 		{filename: "dash", expr: `that-symbol`},
+		{filename: "bang", expr: `(set! x 1)`},
+		{filename: "comparison", expr: `(<= 1 2 (= 3 3))`},
 		{filename: "empty_list", expr: `()`},
-		// {filename: "quote", expr: `'(1 2 3)`},
+		{filename: "float", expr: `3.14159`},
+		{filename: "float_exp", expr: `6.022e23`},
+		{filename: "negative", expr: `(- -5 -2.5)`},
+		{filename: "quote", expr: `'(1 2 3)`},
+		{filename: "backquote", expr: "`(a ,b ,@c)"},
+		{filename: "unbalanced", expr: `(a (b c)`, expectError: true},
+		{filename: "stray_paren", expr: `a)`, expectError: true},
+		{filename: "dotted_pair", expr: `(a . b)`},
+		{filename: "dotted_list", expr: `(1 2 . 3)`},
+		{filename: "dot_first", expr: `(. a)`, expectError: true},
+		{filename: "dot_twice", expr: `(a . b c)`, expectError: true},
+		{filename: "dot_end", expr: `(a .)`, expectError: true},
+		{filename: "dot_alone", expr: `.`, expectError: true},
 	}
 
 	for _, s := range samples {
@@ -78,3 +88,210 @@ func TestParse(t *testing.T) {
 		}
 	}
 } // func TestParse(t *testing.T)
+
+func TestParseNumbers(t *testing.T) {
+	if par == nil {
+		t.SkipNow()
+	}
+
+	type testCase struct {
+		expr     string
+		expected LispValue
+	}
+
+	var cases = []testCase{
+		{expr: "42", expected: Integer{Int: 42}},
+		{expr: "-17", expected: Integer{Int: -17}},
+		{expr: "+3", expected: Integer{Int: 3}},
+		{expr: "2.5", expected: Float{Flt: 2.5}},
+		{expr: "-0.125", expected: Float{Flt: -0.125}},
+		{expr: ".5", expected: Float{Flt: 0.5}},
+		{expr: "1e3", expected: Float{Flt: 1000}},
+		{expr: "1.5E-2", expected: Float{Flt: 0.015}},
+		{expr: "-x", expected: Symbol{Sym: "-X"}},
+		{expr: "-", expected: Symbol{Sym: "-"}},
+	}
+
+	for _, c := range cases {
+		var (
+			err error
+			val *LispValue
+		)
+
+		if val, err = par.ParseString("number", c.expr); err != nil {
+			t.Errorf("Failed to parse %q: %s",
+				c.expr,
+				err.Error())
+		} else if !(*val).Equal(c.expected) {
+			t.Errorf("Parsing %q yielded %s %s (expected %s %s)",
+				c.expr,
+				(*val).Type(),
+				*val,
+				c.expected.Type(),
+				c.expected)
+		}
+	}
+} // func TestParseNumbers(t *testing.T)
+
+func TestFloatString(t *testing.T) {
+	type testCase struct {
+		f   float64
+		str string
+	}
+
+	var cases = []testCase{
+		{f: 1.5, str: "1.5"},
+		{f: 3, str: "3.0"},
+		{f: -0.25, str: "-0.25"},
+		{f: 1e21, str: "1e+21"},
+	}
+
+	for _, c := range cases {
+		if s := (Float{Flt: c.f}).String(); s != c.str {
+			t.Errorf("Unexpected string for %g: %q (expected %q)",
+				c.f,
+				s,
+				c.str)
+		}
+	}
+} // func TestFloatString(t *testing.T)
+
+func TestStringRoundTrip(t *testing.T) {
+	var (
+		par   = New()
+		cases = []string{
+			"plain",
+			`a "quoted" word`,
+			`back\slash`,
+			"tab\tand\nnewline",
+			"ümlaut",
+			`\"(`,
+		}
+	)
+
+	for _, str := range cases {
+		var (
+			err     error
+			val     *LispValue
+			printed = String{Str: str}.String()
+		)
+
+		if val, err = par.ParseString("string", printed); err != nil {
+			t.Errorf("Cannot read printed String %s: %s", printed, err.Error())
+		} else if res, ok := (*val).(String); !ok || res.Str != str {
+			t.Errorf("Reading %s returned %s, expected %q", printed, *val, str)
+		}
+	}
+} // func TestStringRoundTrip(t *testing.T)
+
+func TestReaderMacros(t *testing.T) {
+	if par == nil {
+		t.SkipNow()
+	}
+
+	type testCase struct {
+		expr        string
+		expected    string
+		expectError bool
+	}
+
+	var cases = []testCase{
+		{expr: "'x", expected: "(QUOTE X)"},
+		{expr: "'(1 2 3)", expected: "(QUOTE (1 2 3))"},
+		{expr: "''a", expected: "(QUOTE (QUOTE A))"},
+		{expr: "'()", expected: "(QUOTE NIL)"},
+		{expr: "`x", expected: "(QUASIQUOTE X)"},
+		{expr: "`(a ,b ,@c)", expected: "(QUASIQUOTE (A (UNQUOTE B) (UNQUOTE-SPLICING C)))"},
+		{expr: "`(a `(b ,(c ,x)))", expected: "(QUASIQUOTE (A (QUASIQUOTE (B (UNQUOTE (C (UNQUOTE X)))))))"},
+		{expr: "'", expectError: true},
+		{expr: "(a ')", expectError: true},
+	}
+
+	for _, c := range cases {
+		var (
+			err error
+			val *LispValue
+		)
+
+		if val, err = par.ParseString("macro", c.expr); err != nil {
+			if !c.expectError {
+				t.Errorf("Failed to parse %q: %s",
+					c.expr,
+					err.Error())
+			}
+		} else if c.expectError {
+			t.Errorf("Parsing %q should have failed, but returned %s",
+				c.expr,
+				*val)
+		} else if s := (*val).String(); s != c.expected {
+			t.Errorf("Parsing %q yielded %s (expected %s)",
+				c.expr,
+				s,
+				c.expected)
+		}
+	}
+} // func TestReaderMacros(t *testing.T)
+
+func TestConsCells(t *testing.T) {
+	if par == nil {
+		t.SkipNow()
+	}
+
+	type testCase struct {
+		expr     string
+		expected string
+		length   int
+		proper   bool
+	}
+
+	var cases = []testCase{
+		{expr: "(a b c)", expected: "(A B C)", length: 3, proper: true},
+		{expr: "(a . b)", expected: "(A . B)", length: 1},
+		{expr: "(a b . c)", expected: "(A B . C)", length: 2},
+		{expr: "(a . (b . (c . nil)))", expected: "(A B C)", length: 3, proper: true},
+		{expr: "(a . (b c))", expected: "(A B C)", length: 3, proper: true},
+		{expr: "((a . 1) (b . 2.5))", expected: "((A . 1) (B . 2.5))", length: 2, proper: true},
+		{expr: "(a . ())", expected: "(A)", length: 1, proper: true},
+	}
+
+	for _, c := range cases {
+		var (
+			err  error
+			val  *LispValue
+			cell *ConsCell
+			ok   bool
+		)
+
+		if val, err = par.ParseString("cons", c.expr); err != nil {
+			t.Errorf("Failed to parse %q: %s", c.expr, err.Error())
+		} else if cell, ok = (*val).(*ConsCell); !ok {
+			t.Errorf("Parsing %q yielded a %T, not a ConsCell", c.expr, *val)
+		} else if s := cell.String(); s != c.expected {
+			t.Errorf("Parsing %q yielded %s (expected %s)", c.expr, s, c.expected)
+		} else if n := cell.Length(); n != c.length {
+			t.Errorf("Length of %s is %d, expected %d", cell, n, c.length)
+		} else if cell.IsProper() != c.proper {
+			t.Errorf("IsProper of %s should be %t", cell, c.proper)
+		} else if _, ok = ListItems(cell); ok != c.proper {
+			t.Errorf("ListItems of %s should succeed only for proper lists", cell)
+		}
+	}
+
+	var (
+		l1 = MakeList(Integer{Int: 1}, Integer{Int: 2})
+		l2 = Cons(Integer{Int: 1}, &ConsCell{Car: Integer{Int: 2}})
+		l3 = MakeDotted(Integer{Int: 3}, Integer{Int: 1}, Integer{Int: 2})
+	)
+
+	if !l1.Equal(l2) || !l2.Equal(l1) {
+		t.Errorf("%s and %s should be equal", l1, l2)
+	} else if l1.Equal(l3) || l3.Equal(l1) {
+		t.Errorf("%s and %s should not be equal", l1, l3)
+	} else if !IsNil(MakeList()) {
+		t.Errorf("MakeList without items should return NIL, not %s", MakeList())
+	} else if !(Symbol{Sym: "NIL"}).Equal(nil) || (Symbol{Sym: "T"}).Equal(nil) {
+		t.Error("NIL should be equal to nil, and only NIL")
+	} else if !(&ConsCell{Car: Symbol{Sym: "NIL"}}).Equal(&ConsCell{}) {
+		t.Error("A ConsCell with a nil Car should be equal to (NIL)")
+	}
+} // func TestConsCells(t *testing.T)
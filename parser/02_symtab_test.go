@@ -0,0 +1,95 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/parser/02_symtab_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 19. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-19 00:40:12 krylon>
+
+package parser
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestIntern(t *testing.T) {
+	var (
+		a1 = Intern("FOO")
+		a2 = Intern("FOO")
+		a3 = Intern("foo")
+	)
+
+	if a1 != a2 {
+		t.Error("Interning FOO twice returned different Atoms")
+	} else if a1 == a3 {
+		t.Error("FOO and foo were interned as the same Atom")
+	} else if a1.Name() != "FOO" {
+		t.Errorf("Atom for FOO has name %q", a1.Name())
+	} else if MakeSymbol("FOO").Atom() != a1 {
+		t.Error("MakeSymbol did not return the interned Atom")
+	} else if (Symbol{Sym: "FOO"}).Atom() != a1 {
+		t.Error("A Symbol literal did not find the interned Atom")
+	}
+
+	var (
+		wg    sync.WaitGroup
+		atoms = make([]*Atom, 8)
+	)
+
+	for i := range atoms {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			atoms[i] = Intern("CONCURRENT")
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, a := range atoms {
+		if a != atoms[0] {
+			t.Fatal("Concurrent calls to Intern returned different Atoms")
+		}
+	}
+} // func TestIntern(t *testing.T)
+
+func TestUninterned(t *testing.T) {
+	var (
+		u1 = MakeUninterned("#:G1")
+		u2 = MakeUninterned("#:G1")
+	)
+
+	if u1.Atom() == Intern("#:G1") {
+		t.Error("MakeUninterned returned the interned Atom")
+	} else if u1.Atom() == u2.Atom() {
+		t.Error("Two uninterned Symbols of the same name share an Atom")
+	} else if !Eq(u1, u1) || Eq(u1, u2) {
+		t.Error("An uninterned Symbol must only be EQ to itself")
+	} else if u1.Equal(u2) || u1.Equal(MakeSymbol("#:G1")) {
+		t.Error("An uninterned Symbol must only be EQUAL to itself")
+	}
+} // func TestUninterned(t *testing.T)
+
+func TestReadInterned(t *testing.T) {
+	var (
+		err  error
+		val  *LispValue
+		prog = New()
+	)
+
+	if val, err = prog.ParseString("intern", "(foo\n  (bar foo))"); err != nil {
+		t.Fatalf("Cannot parse: %s", err.Error())
+	}
+
+	var (
+		outer, _ = ListItems(*val)
+		inner, _ = ListItems(outer[1])
+		foo1     = outer[0].(Symbol)
+		foo2     = inner[1].(Symbol)
+	)
+
+	if foo1.Pos == foo2.Pos {
+		t.Errorf("Both FOOs were read at the same position %s", foo1.Pos)
+	} else if foo1.Atom() != foo2.Atom() || foo1.Atom() != Intern("FOO") {
+		t.Error("Symbols of the same name read at different positions have different Atoms")
+	}
+} // func TestReadInterned(t *testing.T)
@@ -0,0 +1,114 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/parser/03_hashtable_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 19. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-19 03:02:51 krylon>
+
+package parser
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHashTableOps(t *testing.T) {
+	var (
+		table = NewHashTable(HashEqual)
+		key   = MakeList(Integer{Int: 1}, String{Str: "x"})
+	)
+
+	table.Put(key, Integer{Int: 1})
+	table.Put(MakeSymbol("B"), nil)
+	table.Put(MakeList(Integer{Int: 1}, String{Str: "x"}), Integer{Int: 2})
+
+	if n := table.Count(); n != 2 {
+		t.Errorf("HashTable has %d entries, expected 2", n)
+	} else if val, ok := table.Get(MakeList(Integer{Int: 1}, String{Str: "x"})); !ok || !val.Equal(Integer{Int: 2}) {
+		t.Errorf("Lookup of %s returned %v, %t", key, val, ok)
+	} else if val, ok = table.Get(MakeSymbol("B")); !ok || !IsNil(val) {
+		t.Errorf("Lookup of B returned %v, %t", val, ok)
+	} else if s := table.String(); s != `#H(:TEST EQUAL ((1 "x") . 2) (B))` {
+		t.Errorf("Unexpected printed representation %s", s)
+	} else if !table.Remove(key) || table.Remove(key) || table.Count() != 1 {
+		t.Errorf("Removing %s failed", key)
+	}
+
+	var eql = NewHashTable(HashEql)
+
+	eql.Put(Float{Flt: 0}, MakeSymbol("ZERO"))
+	eql.Put(Float{Flt: math.NaN()}, MakeSymbol("NAN"))
+
+	if _, ok := eql.Get(Float{Flt: 0}); !ok {
+		t.Error("0.0 should find the entry for 0.0")
+	} else if _, ok = eql.Get(Float{Flt: math.Copysign(0, -1)}); ok {
+		t.Error("-0.0 should not find the entry for 0.0")
+	} else if _, ok = eql.Get(Integer{Int: 0}); ok {
+		t.Error("0 should not find the entry for 0.0")
+	} else if val, _ := eql.Get(Float{Flt: math.NaN()}); !Equal(val, MakeSymbol("NAN")) {
+		t.Errorf("NaN should find the entry for NaN, not %v", val)
+	}
+
+	var (
+		outer = NewHashTable(HashEql)
+		inner = NewHashTable(HashEql)
+	)
+
+	outer.Put(Integer{Int: 1}, outer)
+	outer.Put(Integer{Int: 2}, MakeList(inner, outer))
+	inner.Put(MakeSymbol("UP"), outer)
+
+	if s, expected := outer.String(), "#H(:TEST EQL (1 . #<HASH-TABLE>) (2 #H(:TEST EQL (UP . #<HASH-TABLE>)) #<HASH-TABLE>))"; s != expected {
+		t.Errorf("Unexpected printed representation of circular HashTable:\n%s\nexpected\n%s",
+			s,
+			expected)
+	} else if s = outer.String(); s != expected {
+		t.Errorf("Printing a circular HashTable twice gave different results: %s", s)
+	}
+} // func TestHashTableOps(t *testing.T)
+
+func TestReadHashTable(t *testing.T) {
+	type testCase struct {
+		src         string
+		expected    string
+		count       int
+		expectError bool
+	}
+
+	var (
+		prog  = New()
+		cases = []testCase{
+			{src: "#h()", expected: "#H(:TEST EQL)"},
+			{src: "#H((a . 1) (b 2 3))", expected: "#H(:TEST EQL (A . 1) (B 2 3))", count: 2},
+			{src: `#h(:test equal ("k" . v))`, expected: `#H(:TEST EQUAL ("k" . V))`, count: 1},
+			{src: "#h(:test eq)", expected: "#H(:TEST EQ)"},
+			{src: "(x #h((a . 1)))", expected: "(X #H(:TEST EQL (A . 1)))"},
+			{src: "#h(:test)", expectError: true},
+			{src: "#h(:test foo)", expectError: true},
+			{src: "#h(:test \"eq\")", expectError: true},
+			{src: "#h(a)", expectError: true},
+			{src: "#h((a . 1) . b)", expectError: true},
+			{src: "#h((a . 1)", expectError: true},
+		}
+	)
+
+	for _, c := range cases {
+		var val, err = prog.ParseString("hash", c.src)
+
+		if err != nil {
+			if !c.expectError {
+				t.Errorf("Error parsing %q: %s", c.src, err.Error())
+			}
+			continue
+		} else if c.expectError {
+			t.Errorf("Parsing %q should have failed, but returned %s", c.src, *val)
+			continue
+		} else if s := (*val).String(); s != c.expected {
+			t.Errorf("Parsing %q returned %s, expected %s", c.src, s, c.expected)
+		} else if table, ok := (*val).(*HashTable); ok && table.Count() != c.count {
+			t.Errorf("HashTable read from %q has %d entries, expected %d",
+				c.src,
+				table.Count(),
+				c.count)
+		}
+	}
+} // func TestReadHashTable(t *testing.T)
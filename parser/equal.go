@@ -0,0 +1,58 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/parser/equal.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 19. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-19 01:12:33 krylon>
+
+package parser
+
+import "math"
+
+// Eq, Eql and Equal implement the Lisp predicates EQ, EQL and EQUAL. All
+// three treat nil as NIL, the empty List.
+
+// Eq returns true if a and b are the same object. Numbers have no identity
+// apart from their value, so Eq treats them like Eql does.
+func Eq(a, b LispValue) bool {
+	return Eql(a, b)
+} // func Eq(a, b LispValue) bool
+
+// Eql returns true if a and b are the same object, or numbers of the same
+// type and value. Floats are the same if they have the same representation,
+// so 0.0 and -0.0 differ, while NaN is the same as itself.
+func Eql(a, b LispValue) bool {
+	switch x := a.(type) {
+	case nil:
+		return IsNil(b)
+	case Symbol:
+		if x.Sym == "NIL" {
+			return IsNil(b)
+		}
+
+		var y, ok = b.(Symbol)
+		return ok && x.Atom() == y.Atom()
+	case Integer:
+		var y, ok = b.(Integer)
+		return ok && x.Int == y.Int
+	case Float:
+		var y, ok = b.(Float)
+		return ok && math.Float64bits(x.Flt) == math.Float64bits(y.Flt)
+	case String:
+		var y, ok = b.(String)
+		return ok && x.id != nil && x.id == y.id
+	default:
+		// All other LispValues are pointers, which are the same
+		// object if they are the same pointer.
+		return a == b
+	}
+} // func Eql(a, b LispValue) bool
+
+// Equal returns true if a and b are structurally equal, as defined by the
+// Equal method of LispValue.
+func Equal(a, b LispValue) bool {
+	if a == nil || b == nil {
+		return IsNil(a) && IsNil(b)
+	}
+
+	return a.Equal(b)
+} // func Equal(a, b LispValue) bool
@@ -0,0 +1,324 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/parser/hashtable.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 19. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-19 01:47:20 krylon>
+
+package parser
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/blicero/krylisp/types"
+)
+
+// HashTest is the predicate a HashTable uses to compare keys.
+type HashTest uint8
+
+// A HashTable compares its keys with EQ, EQL or EQUAL.
+const (
+	HashEq HashTest = iota
+	HashEql
+	HashEqual
+)
+
+var hashTestNames = [...]string{"EQ", "EQL", "EQUAL"}
+
+func (t HashTest) String() string { return hashTestNames[t] }
+
+// HashTestNamed returns the HashTest for the name of its predicate.
+func HashTestNamed(name string) (HashTest, bool) {
+	for i, n := range hashTestNames {
+		if n == name {
+			return HashTest(i), true
+		}
+	}
+
+	return HashEql, false
+} // func HashTestNamed(name string) (HashTest, bool)
+
+// same returns true if the keys a and b are the same under the test.
+func (t HashTest) same(a, b LispValue) bool {
+	switch t {
+	case HashEq:
+		return Eq(a, b)
+	case HashEql:
+		return Eql(a, b)
+	default:
+		return Equal(a, b)
+	}
+} // func (t HashTest) same(a, b LispValue) bool
+
+// hashBudget is the number of ConsCells of a key an EQUAL HashTable looks
+// at to compute its hash. Keys that are EQUAL share at least that prefix,
+// and the limit keeps hashing of long or circular Lists cheap.
+const hashBudget = 64
+
+type hashEntry struct {
+	key LispValue
+	val LispValue
+	seq uint64
+}
+
+// HashTable maps keys to values. It remembers the order in which keys were
+// first added, and iterates and prints its entries in that order.
+//
+// The printed representation is #H(:TEST EQL (key . value)...), which the
+// reader reads back as a HashTable literal. A HashTable that contains
+// itself, directly or through other values, is printed as #<HASH-TABLE>
+// where it appears within itself.
+type HashTable struct {
+	Pos      lexer.Position
+	test     HashTest
+	buckets  map[uint64][]*hashEntry
+	count    int
+	seq      uint64
+	printing bool
+}
+
+// NewHashTable creates an empty HashTable that compares its keys with test.
+func NewHashTable(test HashTest) *HashTable {
+	return &HashTable{
+		test:    test,
+		buckets: make(map[uint64][]*hashEntry),
+	}
+} // func NewHashTable(test HashTest) *HashTable
+
+// Type returns the type of the receiver, i.e. types.HashTable
+func (t *HashTable) Type() types.Type { return types.HashTable }
+
+// Equal compares the receiver to another LispValue for equality.
+// HashTables are only equal to themselves.
+func (t *HashTable) Equal(other LispValue) bool {
+	var o, ok = other.(*HashTable)
+
+	return ok && o == t
+} // func (t *HashTable) Equal(other LispValue) bool
+
+// String returns the printed representation of the HashTable.
+func (t *HashTable) String() string {
+	if t.printing {
+		return "#<HASH-TABLE>"
+	}
+
+	var sb strings.Builder
+
+	t.printing = true
+	defer func() { t.printing = false }()
+
+	sb.WriteString("#H(:TEST ")
+	sb.WriteString(t.test.String())
+
+	for _, e := range t.entries() {
+		sb.WriteString(" ")
+		sb.WriteString(Cons(e.key, e.val).String())
+	}
+
+	sb.WriteString(")")
+
+	return sb.String()
+} // func (t *HashTable) String() string
+
+// Test returns the predicate the HashTable compares its keys with.
+func (t *HashTable) Test() HashTest { return t.test }
+
+// Count returns the number of entries in the HashTable.
+func (t *HashTable) Count() int { return t.count }
+
+// find returns the hash of key and the index of its entry in the bucket,
+// or -1 if the HashTable has no entry for key.
+func (t *HashTable) find(key LispValue) (uint64, int) {
+	var h = t.hash(key)
+
+	for i, e := range t.buckets[h] {
+		if t.test.same(e.key, key) {
+			return h, i
+		}
+	}
+
+	return h, -1
+} // func (t *HashTable) find(key LispValue) (uint64, int)
+
+// Get returns the value for key, and whether the HashTable has an entry
+// for it.
+func (t *HashTable) Get(key LispValue) (LispValue, bool) {
+	var h, i = t.find(key)
+
+	if i < 0 {
+		return nil, false
+	}
+
+	return t.buckets[h][i].val, true
+} // func (t *HashTable) Get(key LispValue) (LispValue, bool)
+
+// Put sets the value for key. If the HashTable already has an entry for
+// key, its value is replaced, but the entry keeps its place in the order.
+func (t *HashTable) Put(key, val LispValue) {
+	if key == nil {
+		key = MakeSymbol("NIL")
+	}
+
+	if val == nil {
+		val = MakeSymbol("NIL")
+	}
+
+	var h, i = t.find(key)
+
+	if i >= 0 {
+		t.buckets[h][i].val = val
+		return
+	}
+
+	t.seq++
+	t.count++
+	t.buckets[h] = append(t.buckets[h], &hashEntry{key: key, val: val, seq: t.seq})
+} // func (t *HashTable) Put(key, val LispValue)
+
+// Remove deletes the entry for key and returns true, or returns false if
+// there was none.
+func (t *HashTable) Remove(key LispValue) bool {
+	var h, i = t.find(key)
+
+	if i < 0 {
+		return false
+	}
+
+	var bucket = t.buckets[h]
+
+	if len(bucket) == 1 {
+		delete(t.buckets, h)
+	} else {
+		t.buckets[h] = append(bucket[:i:i], bucket[i+1:]...)
+	}
+
+	t.count--
+
+	return true
+} // func (t *HashTable) Remove(key LispValue) bool
+
+// entries returns the entries of the HashTable in the order they were
+// added.
+func (t *HashTable) entries() []*hashEntry {
+	var list = make([]*hashEntry, 0, t.count)
+
+	for _, bucket := range t.buckets {
+		list = append(list, bucket...)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].seq < list[j].seq })
+
+	return list
+} // func (t *HashTable) entries() []*hashEntry
+
+// Keys returns the keys of the HashTable in the order they were added.
+func (t *HashTable) Keys() []LispValue {
+	var (
+		list = t.entries()
+		keys = make([]LispValue, len(list))
+	)
+
+	for i, e := range list {
+		keys[i] = e.key
+	}
+
+	return keys
+} // func (t *HashTable) Keys() []LispValue
+
+// Each calls fn for every entry of the HashTable, in the order they were
+// added, and stops at the first error. fn may modify the HashTable; it
+// sees the entries as they were when Each was called.
+func (t *HashTable) Each(fn func(key, val LispValue) error) error {
+	for _, e := range t.entries() {
+		if err := fn(e.key, e.val); err != nil {
+			return err
+		}
+	}
+
+	return nil
+} // func (t *HashTable) Each(fn func(key, val LispValue) error) error
+
+// hash computes the hash of key. Keys that are the same under the test of
+// the HashTable have the same hash.
+func (t *HashTable) hash(key LispValue) uint64 {
+	var (
+		h      = fnv.New64a()
+		budget = hashBudget
+	)
+
+	hashValue(h, t.test, key, &budget)
+
+	return h.Sum64()
+} // func (t *HashTable) hash(key LispValue) uint64
+
+// hashValue writes the parts of v that determine its identity under test
+// to h. Only EQUAL looks inside of ConsCells, for EQ and EQL they are
+// identified by their address, like all other values that are pointers.
+func hashValue(h hash.Hash64, test HashTest, v LispValue, budget *int) {
+	var buf [9]byte
+
+	switch x := v.(type) {
+	case nil:
+		hashValue(h, test, MakeSymbol("NIL"), budget)
+	case Symbol:
+		buf[0] = 's'
+		h.Write(buf[:1])
+		h.Write([]byte(x.Sym))
+	case Integer:
+		buf[0] = 'i'
+		binary.LittleEndian.PutUint64(buf[1:], uint64(x.Int))
+		h.Write(buf[:])
+	case Float:
+		buf[0] = 'f'
+		binary.LittleEndian.PutUint64(buf[1:], math.Float64bits(x.Flt))
+		h.Write(buf[:])
+	case String:
+		buf[0] = 'S'
+		h.Write(buf[:1])
+		h.Write([]byte(x.Str))
+	case *ConsCell:
+		if test != HashEqual {
+			hashPointer(h, v)
+			return
+		}
+
+		for ; *budget > 0; *budget-- {
+			buf[0] = 'c'
+			h.Write(buf[:1])
+			hashValue(h, test, x.Car, budget)
+
+			var next, ok = x.Tail().(*ConsCell)
+
+			if !ok {
+				hashValue(h, test, x.Tail(), budget)
+				return
+			}
+
+			x = next
+		}
+	default:
+		hashPointer(h, v)
+	}
+} // func hashValue(h hash.Hash64, test HashTest, v LispValue, budget *int)
+
+// hashPointer writes the address of v to h.
+func hashPointer(h hash.Hash64, v LispValue) {
+	var (
+		buf [9]byte
+		rv  = reflect.ValueOf(v)
+	)
+
+	buf[0] = 'p'
+
+	if rv.Kind() == reflect.Pointer {
+		binary.LittleEndian.PutUint64(buf[1:], uint64(rv.Pointer()))
+	}
+
+	h.Write(buf[:])
+} // func hashPointer(h hash.Hash64, v LispValue)
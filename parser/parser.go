@@ -9,6 +9,7 @@ package parser
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
@@ -19,39 +20,71 @@ import (
 )
 
 var lex = lexer.MustSimple([]lexer.SimpleRule{
-	{Name: `Symbol`, Pattern: `[-+*/%:a-zA-Z<>][-+*/%:a-zA-Z\d<>]*`},
-	{Name: `Integer`, Pattern: `\d+`},
-	{Name: `String`, Pattern: `"(?:[^\"]*)"`},
+	{Name: `Float`, Pattern: `[-+]?(\d*\.\d+([eE][-+]?\d+)?|\d+[eE][-+]?\d+)`},
+	{Name: `Dot`, Pattern: `\.`},
+	{Name: `Integer`, Pattern: `[-+]?\d+`},
+	{Name: `Symbol`, Pattern: `[-+*/%:&a-zA-Z<>=!?_][-+*/%:&a-zA-Z\d<>=!?_]*`},
+	{Name: `String`, Pattern: `"(?:\\.|[^\\"])*"`},
+	{Name: `HashOpen`, Pattern: `#[hH]\(`},
 	{Name: `OpenParen`, Pattern: `\(`},
 	{Name: `CloseParen`, Pattern: `\)`},
 	{Name: `Blank`, Pattern: `\s+`},
+	{Name: `Comment`, Pattern: `;[^\n]*`},
 	{Name: `Quote`, Pattern: `'`},
+	{Name: `Backquote`, Pattern: "`"},
+	{Name: `UnquoteSplicing`, Pattern: `,@`},
+	{Name: `Unquote`, Pattern: `,`},
 })
 
-// New creates a new Parser.
-func New() *participle.Parser[LispValue] {
-	par := participle.MustBuild[LispValue](
+func options() []participle.Option {
+	return []participle.Option{
 		participle.Lexer(lex),
 		participle.Unquote("String"),
-		participle.Elide("Blank"),
+		participle.Elide("Blank", "Comment"),
 		participle.Upper("Symbol"),
-		participle.Union[LispValue](Symbol{}, Integer{}, String{}, List{}),
-	)
+		participle.ParseTypeWith(parseValue),
+	}
+} // func options() []participle.Option
+
+// New creates a new Parser.
+func New() *participle.Parser[LispValue] {
+	par := participle.MustBuild[LispValue](options()...)
 
 	return par
 } // func New() *participle.Parser[LispValue]
 
-// LispValue is the common interface for types in the Lisp Interpreter
+// Program is a sequence of top-level forms, e.g. the content of a source file.
+type Program struct {
+	Forms []LispValue `parser:"@@*"`
+}
+
+// NewProgram creates a Parser that reads any number of top-level forms
+// in one go, as is necessary to load a file.
+func NewProgram() *participle.Parser[Program] {
+	par := participle.MustBuild[Program](options()...)
+
+	return par
+} // func NewProgram() *participle.Parser[Program]
+
+// LispValue is the common interface for types in the Lisp Interpreter.
+// Equal implements EQUAL: Symbols are equal if they have the same name,
+// numbers if they have the same type and value, Strings if they have the
+// same contents, and ConsCells if their Car and Cdr are equal. All other
+// values are only equal to themselves. NIL is equal to nil, which stands
+// for the empty List in a Cdr.
 type LispValue interface {
 	fmt.Stringer
 	Type() types.Type
 	Equal(other LispValue) bool
 }
 
-// Symbol is a symbol.
+// Symbol is a symbol. Symbols of the same name are the same symbol, no
+// matter where they were read from; compare their Atoms rather than the
+// Symbols themselves.
 type Symbol struct {
-	Pos lexer.Position
-	Sym string `parser:"@Symbol"`
+	Pos  lexer.Position
+	Sym  string
+	atom *Atom
 }
 
 // Type returns the type of the Symbol.
@@ -62,11 +95,13 @@ func (s Symbol) String() string   { return s.Sym }
 func (s Symbol) IsKeyword() bool { return s.Sym[0] == ':' }
 
 // Equal compares the receiver to another LispValue for equality.
+// NIL is equal to nil. Symbols are equal if they have the same Atom.
 func (s Symbol) Equal(other LispValue) bool {
-	// TODO Make sure NIL equals ()
 	switch val := other.(type) {
+	case nil:
+		return s.Sym == "NIL"
 	case Symbol:
-		return s.Sym == val.Sym
+		return s.Atom() == val.Atom()
 	default:
 		return false
 	}
@@ -75,7 +110,7 @@ func (s Symbol) Equal(other LispValue) bool {
 // Integer is a signed 64-bit integer
 type Integer struct {
 	Pos lexer.Position
-	Int int64 `parser:"@Integer"`
+	Int int64
 }
 
 // Type returns the type of the receiver
@@ -92,15 +127,68 @@ func (i Integer) Equal(other LispValue) bool {
 	}
 } // func (i Integer) Equal(other LispValue) bool
 
+// Float is a 64-bit IEEE 754 floating point number.
+type Float struct {
+	Pos lexer.Position
+	Flt float64
+}
+
+// Type returns the type of the receiver
+func (f Float) Type() types.Type { return types.Float }
+
+// String returns the printed representation of the receiver. Integral values
+// still get a decimal point, so they are read back as a Float, not an Integer.
+func (f Float) String() string {
+	var s = strconv.FormatFloat(f.Flt, 'g', -1, 64)
+
+	if math.IsInf(f.Flt, 0) || math.IsNaN(f.Flt) || strings.ContainsAny(s, ".e") {
+		return s
+	}
+
+	return s + ".0"
+} // func (f Float) String() string
+
+// Equal compares the receiver to the given LispValue for equality.
+// Like EQUAL in Common Lisp, a Float is never equal to an Integer, even if
+// both denote the same number. Use = to compare numbers of different types.
+func (f Float) Equal(other LispValue) bool {
+	switch o := other.(type) {
+	case Float:
+		return math.Float64bits(f.Flt) == math.Float64bits(o.Flt)
+	default:
+		return false
+	}
+} // func (f Float) Equal(other LispValue) bool
+
 // String is a ... string.
+//
+// Strings are values in Go, but objects in Lisp: every String the reader
+// or MakeString creates has its own identity, which all copies of it share.
+// EQ and EQL compare Strings by that identity. Strings created as literals
+// in Go have none, they are not EQ to anything.
 type String struct {
 	Pos lexer.Position
-	Str string `parser:"@String"`
+	Str string
+	id  *identity
 }
 
+// identity marks a String as one object. It must not be of size zero, or
+// all identities could share the same address.
+type identity struct {
+	_ byte
+}
+
+// MakeString creates a new String object with the given content.
+func MakeString(s string) String {
+	return String{Str: s, id: new(identity)}
+} // func MakeString(s string) String
+
 // Type returns the type of the receiver.
 func (s String) Type() types.Type { return types.String }
-func (s String) String() string   { return `"` + s.Str + `"` }
+
+// String returns the printed representation of the receiver, with quotes
+// and backslashes escaped the way the reader expects them.
+func (s String) String() string { return strconv.Quote(s.Str) }
 
 // Equal compares the receiver to the given LispValue for equality.
 func (s String) Equal(other LispValue) bool {
@@ -112,178 +200,204 @@ func (s String) Equal(other LispValue) bool {
 	}
 } // func (s String) Equal(other LispValue) bool
 
-// FIXME Lists shall be made of ConsCells (which I still need to implement), not slices!!!
-
-// ConsCell is the basic building block of Lisp Lists.
+// ConsCell is a cons cell, a pair of two values called Car and Cdr, from
+// which Lists are built: A proper List is a chain of ConsCells linked by
+// their Cdr, and the Cdr of the last one is NIL. A List whose last Cdr is
+// something other than NIL is improper; a single ConsCell like that is a
+// dotted pair, e.g. (A . B). The empty List is the Symbol NIL.
+// A Cdr of nil counts as NIL, so a ConsCell literal without a Cdr is a List
+// of one element.
 type ConsCell struct {
 	Pos lexer.Position
-	Car LispValue `parser:"@@"`
-	Cdr *ConsCell `parser:"@@*"`
+	Car LispValue
+	Cdr LispValue
 }
 
-// // ConsCell is the basic building block of Lisp Lists.
-// type ConsCell struct {
-// 	Car LispValue `parser:"@"`
-// 	Cdr *ConsCell `parser:"@@*"`
-// }
-
-func (c ConsCell) String() string {
-	if c.Cdr != nil {
-		return fmt.Sprintf("(%s . %s)",
-			c.Car,
-			c.Cdr)
+// Cons returns a fresh ConsCell of car and cdr.
+func Cons(car, cdr LispValue) *ConsCell {
+	return &ConsCell{Car: car, Cdr: cdr}
+} // func Cons(car, cdr LispValue) *ConsCell
+
+// IsNil returns true if v is NIL, the empty List.
+func IsNil(v LispValue) bool {
+	if v == nil {
+		return true
 	}
 
-	return fmt.Sprintf("(%s . NIL)",
-		c.Car)
-} // func (c ConsCell) String() string
+	var s, ok = v.(Symbol)
 
-// Type returns the type of the receiver.
-func (c ConsCell) Type() types.Type {
-	return types.ConsCell
-} // func (c ConsCell) Type() types.Type
+	return ok && s.Sym == "NIL"
+} // func IsNil(v LispValue) bool
 
-// Equal compares the receiver to the given LispValue for equality.
-func (c ConsCell) Equal(other LispValue) bool {
-	switch o := other.(type) {
-	case ConsCell:
-		if c.Car.Equal(o.Car) {
-			if c.Cdr == nil {
-				return o.Cdr == nil
-			}
-			return c.Cdr.Equal(o.Cdr)
-		}
-		return false
-	default:
-		return false
+// Rest returns the Cdr of the receiver if it is a ConsCell, i.e. the rest
+// of the List, or nil at the end of the List.
+func (c *ConsCell) Rest() *ConsCell {
+	var next, _ = c.Cdr.(*ConsCell)
+
+	return next
+} // func (c *ConsCell) Rest() *ConsCell
+
+// Tail returns the Cdr of the receiver, with nil replaced by NIL.
+func (c *ConsCell) Tail() LispValue {
+	if c.Cdr == nil {
+		return MakeSymbol("NIL")
 	}
-} // func (c ConsCell) Equal(other LispValue) bool
 
-// List is a Lisp list.
-type List struct {
-	Pos lexer.Position
-	Car LispValue `parser:"OpenParen @@?"`
-	Cdr *ConsCell `parser:"(@@*)? CloseParen"`
-	// Items []LispValue `parser:"OpenParen @@* CloseParen"`
-	// Items ConsCell `parser:"( @@LispValue @@ConsCell* )"`
-}
+	return c.Cdr
+} // func (c *ConsCell) Tail() LispValue
+
+// last returns the last ConsCell of the List starting at the receiver.
+func (c *ConsCell) last() *ConsCell {
+	for next := c.Rest(); next != nil; next = c.Rest() {
+		c = next
+	}
+
+	return c
+} // func (c *ConsCell) last() *ConsCell
+
+// IsProper returns true if the receiver is a proper List.
+func (c *ConsCell) IsProper() bool {
+	return IsNil(c.last().Cdr)
+} // func (c *ConsCell) IsProper() bool
 
 // Type returns the type of the receiver.
-func (l List) Type() types.Type { return types.List }
+func (c *ConsCell) Type() types.Type {
+	return types.ConsCell
+} // func (c *ConsCell) Type() types.Type
 
-func (l List) String() string {
-	var (
-		sb   strings.Builder
-		cons = l.Cdr
-	)
+// String returns the printed representation of the receiver. Proper Lists
+// are printed as (a b c), improper ones as (a b . c).
+func (c *ConsCell) String() string {
+	var sb strings.Builder
 
 	sb.WriteString("(")
-	//sb.WriteString(l.Items.String())
-	if l.Car != nil {
-		sb.WriteString(l.Car.String())
-	}
 
-	for cons != nil {
-		sb.WriteString(" ")
-		if cons.Car != nil {
-			sb.WriteString(cons.Car.String())
+	for cell := c; ; {
+		if cell.Car != nil {
+			sb.WriteString(cell.Car.String())
 		} else {
 			sb.WriteString("<nil>")
 		}
-		cons = cons.Cdr
-	}
 
-	sb.WriteString(")")
+		if next := cell.Rest(); next != nil {
+			sb.WriteString(" ")
+			cell = next
+		} else {
+			if !IsNil(cell.Cdr) {
+				sb.WriteString(" . ")
+				sb.WriteString(cell.Cdr.String())
+			}
 
-	// for i, v := range l.Items {
-	// 	items[i] = v.String()
-	// }
+			break
+		}
+	}
 
-	// sb.WriteString("(")
-	// sb.WriteString(strings.Join(items, " "))
-	// sb.WriteString(")")
+	sb.WriteString(")")
 
 	return sb.String()
-} // func (l List) String() string
-
-// Length returns the length of the receiver.
-func (l List) Length() int {
-	var (
-		cnt  = 0
-		cons = l.Cdr
-	)
+} // func (c *ConsCell) String() string
 
-	if l.Car == nil {
-		return 0
-	}
-
-	cnt++
+// Length returns the number of ConsCells in the List starting at the
+// receiver. For a proper List, that is the number of elements.
+func (c *ConsCell) Length() int {
+	var cnt = 0
 
-	for cons != nil {
+	for ; c != nil; c = c.Rest() {
 		cnt++
-		cons = cons.Cdr
 	}
 
 	return cnt
-} // func (l List) Length() int
+} // func (c *ConsCell) Length() int
 
-// Equal compares the receiver to the given LispValue for equality.
-func (l List) Equal(other LispValue) bool {
-	switch o := other.(type) {
-	case Symbol:
-		return o.Sym == "NIL" && l.Length() == 0
-	case List:
-		if l.Length() != o.Length() {
-			return false
-		} else if !l.Car.Equal(o.Car) {
-			return false
-		}
+// Equal compares the receiver to the given LispValue for equality, i.e.
+// whether both are Lists of equal elements with equal tails.
+func (c *ConsCell) Equal(other LispValue) bool {
+	var o, ok = other.(*ConsCell)
 
-		var c1, c2 = l.Cdr, o.Cdr
+	if !ok {
+		return false
+	}
 
-		for c1 != nil {
-			if !c1.Car.Equal(c2.Car) {
-				return false
-			}
-			c1 = c1.Cdr
-			c2 = c2.Cdr
+	for c != nil && o != nil {
+		if c == o {
+			return true
+		} else if !Equal(c.Car, o.Car) {
+			return false
 		}
 
-		return c2 == nil
+		var n1, n2 = c.Rest(), o.Rest()
 
-		// for idx, val := range l.Items {
-		// 	if !val.Equal(o.Items[idx]) {
-		// 		return false
-		// 	}
-		// }
+		if n1 == nil || n2 == nil {
+			return n1 == n2 && Equal(c.Tail(), o.Tail())
+		}
 
-		// return true
-	default:
-		return false
+		c, o = n1, n2
 	}
-} // func (l List) Equal(other LispValue) bool
+
+	return c == o
+} // func (c *ConsCell) Equal(other LispValue) bool
 
 // At accesses the nth element of the List.
-func (l List) At(idx int) (LispValue, bool) {
+func (c *ConsCell) At(idx int) (LispValue, bool) {
 	if idx < 0 {
 		panic("Index must be >= 0")
-	} else if idx == 0 {
-		return l.Car, true
 	}
 
-	var (
-		cur  = 1
-		cons = l.Cdr
-	)
-
-	for cons != nil {
-		if cur == idx {
-			return cons.Car, true
+	for ; c != nil; c = c.Rest() {
+		if idx == 0 {
+			return c.Car, true
 		}
 
-		cons = cons.Cdr
-		cur++
+		idx--
 	}
 
 	return nil, false
-} // func (l List) At(idx int) (LispValue, bool)
+} // func (c *ConsCell) At(idx int) (LispValue, bool)
+
+// MakeList creates a List from the given values. With no values, it
+// returns the Symbol NIL, which is the empty list.
+func MakeList(items ...LispValue) LispValue {
+	return MakeDotted(MakeSymbol("NIL"), items...)
+} // func MakeList(items ...LispValue) LispValue
+
+// MakeDotted creates a List from the given values, whose last Cdr is tail
+// instead of NIL. With no values, it returns tail.
+func MakeDotted(tail LispValue, items ...LispValue) LispValue {
+	var lst = tail
+
+	for i := len(items) - 1; i >= 0; i-- {
+		lst = &ConsCell{Car: items[i], Cdr: lst}
+	}
+
+	return lst
+} // func MakeDotted(tail LispValue, items ...LispValue) LispValue
+
+// ListItems returns the elements of a proper list as a slice.
+// NIL counts as the empty list. If v is not a proper list, the second
+// return value is false.
+func ListItems(v LispValue) ([]LispValue, bool) {
+	var items []LispValue
+
+	switch l := v.(type) {
+	case Symbol:
+		return nil, l.Sym == "NIL"
+	case *ConsCell:
+		var c = l
+
+		for {
+			items = append(items, c.Car)
+
+			if next := c.Rest(); next != nil {
+				c = next
+			} else if !IsNil(c.Cdr) {
+				return nil, false
+			} else {
+				break
+			}
+		}
+	default:
+		return nil, false
+	}
+
+	return items, true
+} // func ListItems(v LispValue) ([]LispValue, bool)
@@ -0,0 +1,217 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/parser/reader.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 17. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-17 20:41:09 krylon>
+
+package parser
+
+import (
+	"strconv"
+
+	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// participle's struct grammar can only produce values of the type it is
+// parsing into, but the reader macros ('x, `x, ,x and ,@x) have to produce
+// Lists. So instead of a Union, LispValue gets a small hand-written
+// recursive descent parser on top of the participle lexer.
+//
+// The reader reads () as NIL, and (a b . c) as an improper List whose last
+// Cdr is c.
+
+var tokenTypes = lex.Symbols()
+
+// readerMacros maps the tokens of the reader macros to the symbol of the
+// form they expand to.
+var readerMacros = map[lexer.TokenType]string{
+	tokenTypes["Quote"]:           "QUOTE",
+	tokenTypes["Backquote"]:       "QUASIQUOTE",
+	tokenTypes["Unquote"]:         "UNQUOTE",
+	tokenTypes["UnquoteSplicing"]: "UNQUOTE-SPLICING",
+}
+
+// parseValue reads a single LispValue from the token stream.
+// If the next token cannot start a value (e.g. EOF or a closing
+// parenthesis), it returns participle.NextMatch.
+func parseValue(pl *lexer.PeekingLexer) (LispValue, error) {
+	var tok = pl.Peek()
+
+	if tok.EOF() {
+		return nil, participle.NextMatch
+	}
+
+	switch tok.Type {
+	case tokenTypes["Symbol"]:
+		pl.Next()
+		return Symbol{Pos: tok.Pos, Sym: tok.Value, atom: Intern(tok.Value)}, nil
+	case tokenTypes["String"]:
+		pl.Next()
+		return String{Pos: tok.Pos, Str: tok.Value, id: new(identity)}, nil
+	case tokenTypes["Integer"]:
+		pl.Next()
+		var n, err = strconv.ParseInt(tok.Value, 10, 64)
+		if err != nil {
+			return nil, participle.Errorf(tok.Pos, "Invalid integer %s: %s",
+				tok.Value,
+				err.Error())
+		}
+		return Integer{Pos: tok.Pos, Int: n}, nil
+	case tokenTypes["Float"]:
+		pl.Next()
+		var f, err = strconv.ParseFloat(tok.Value, 64)
+		if err != nil {
+			return nil, participle.Errorf(tok.Pos, "Invalid float %s: %s",
+				tok.Value,
+				err.Error())
+		}
+		return Float{Pos: tok.Pos, Flt: f}, nil
+	case tokenTypes["OpenParen"]:
+		return parseList(pl)
+	case tokenTypes["HashOpen"]:
+		return parseHashTable(pl)
+	}
+
+	if name, ok := readerMacros[tok.Type]; ok {
+		pl.Next()
+
+		var val, err = parseValue(pl)
+		if err == participle.NextMatch {
+			return nil, participle.Errorf(tok.Pos, "%s must be followed by an expression",
+				tok.Value)
+		} else if err != nil {
+			return nil, err
+		}
+
+		return &ConsCell{
+			Pos: tok.Pos,
+			Car: Symbol{Pos: tok.Pos, Sym: name, atom: Intern(name)},
+			Cdr: &ConsCell{Pos: tok.Pos, Car: val, Cdr: Symbol{Pos: tok.Pos, Sym: "NIL", atom: Intern("NIL")}},
+		}, nil
+	}
+
+	return nil, participle.NextMatch
+} // func parseValue(pl *lexer.PeekingLexer) (LispValue, error)
+
+// parseList reads a parenthesized list. The next token must be an
+// opening parenthesis.
+func parseList(pl *lexer.PeekingLexer) (LispValue, error) {
+	var (
+		open  = pl.Next()
+		items []LispValue
+		pos             = []lexer.Position{open.Pos}
+		tail  LispValue = Symbol{Pos: open.Pos, Sym: "NIL", atom: Intern("NIL")}
+	)
+
+	for {
+		var tok = pl.Peek()
+
+		if tok.EOF() {
+			return nil, &participle.UnexpectedTokenError{
+				Unexpected: *tok,
+				Expect:     "<closeparen>",
+			}
+		} else if tok.Type == tokenTypes["CloseParen"] {
+			pl.Next()
+			break
+		} else if tok.Type == tokenTypes["Dot"] {
+			var err error
+
+			if len(items) == 0 {
+				return nil, participle.Errorf(tok.Pos, "Dot must follow at least one element of a list")
+			}
+
+			pl.Next()
+
+			if tail, err = parseValue(pl); err == participle.NextMatch {
+				return nil, participle.Errorf(tok.Pos, "Dot must be followed by an expression")
+			} else if err != nil {
+				return nil, err
+			} else if end := pl.Next(); end.Type != tokenTypes["CloseParen"] {
+				return nil, &participle.UnexpectedTokenError{
+					Unexpected: *end,
+					Expect:     "<closeparen>",
+				}
+			}
+
+			break
+		}
+
+		var val, err = parseValue(pl)
+		if err == participle.NextMatch {
+			return nil, &participle.UnexpectedTokenError{Unexpected: *tok}
+		} else if err != nil {
+			return nil, err
+		}
+
+		items = append(items, val)
+
+		if len(items) > 1 {
+			pos = append(pos, tok.Pos)
+		}
+	}
+
+	var lst = tail
+
+	for i := len(items) - 1; i >= 0; i-- {
+		lst = &ConsCell{Pos: pos[i], Car: items[i], Cdr: lst}
+	}
+
+	return lst, nil
+} // func parseList(pl *lexer.PeekingLexer) (LispValue, error)
+
+// parseHashTable reads a HashTable literal, #H(:TEST test (key . value)...).
+// The :TEST is optional and defaults to EQL. The next token must be the
+// opening #H(.
+//
+// The HashTable is created when the literal is read, so a form that
+// contains one returns the same HashTable every time it is evaluated.
+func parseHashTable(pl *lexer.PeekingLexer) (LispValue, error) {
+	var (
+		err   error
+		lst   LispValue
+		open  = pl.Peek()
+		items []LispValue
+		ok    bool
+		test  = HashEql
+	)
+
+	if lst, err = parseList(pl); err != nil {
+		return nil, err
+	} else if items, ok = ListItems(lst); !ok {
+		return nil, participle.Errorf(open.Pos, "HashTable literal must not be a dotted list")
+	}
+
+	if len(items) > 0 && items[0].String() == ":TEST" {
+		var name Symbol
+
+		if len(items) < 2 {
+			return nil, participle.Errorf(open.Pos, "HashTable literal needs a test after :TEST")
+		} else if name, ok = items[1].(Symbol); !ok {
+			return nil, participle.Errorf(open.Pos, "Test for HashTable must be a Symbol, not %s",
+				items[1])
+		} else if test, ok = HashTestNamed(name.Sym); !ok {
+			return nil, participle.Errorf(open.Pos, "Invalid test for HashTable: %s", name)
+		}
+
+		items = items[2:]
+	}
+
+	var table = NewHashTable(test)
+
+	table.Pos = open.Pos
+
+	for _, item := range items {
+		var entry, isCons = item.(*ConsCell)
+
+		if !isCons {
+			return nil, participle.Errorf(open.Pos, "Entry of HashTable literal must be a pair (key . value), not %s",
+				item)
+		}
+
+		table.Put(entry.Car, entry.Tail())
+	}
+
+	return table, nil
+} // func parseHashTable(pl *lexer.PeekingLexer) (LispValue, error)
@@ -0,0 +1,81 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/parser/symtab.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 19. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-19 00:21:47 krylon>
+
+package parser
+
+import "sync"
+
+// A Symbol is a name plus the position it was read from, so two Symbols of
+// the same name are different Go values. The symbol table interns names
+// into Atoms: There is exactly one Atom per name, so Atoms can be compared,
+// and used as map keys, by identity, regardless of where a Symbol was read.
+//
+// Atoms are never removed from the table, so every name ever used stays in
+// memory. Uninterned Symbols, such as those made by GENSYM, have an Atom of
+// their own that is not in the table. They are only ever identical to
+// themselves, and they are freed once they are no longer used.
+
+// Atom is the unique object the symbol table holds for a symbol name.
+type Atom struct {
+	name string
+}
+
+// Name returns the name of the Atom.
+func (a *Atom) Name() string   { return a.name }
+func (a *Atom) String() string { return a.name }
+
+var symtab = struct {
+	sync.RWMutex
+	atoms map[string]*Atom
+}{
+	atoms: make(map[string]*Atom),
+}
+
+// Intern returns the Atom for name, adding it to the symbol table if it is
+// not there, yet.
+func Intern(name string) *Atom {
+	symtab.RLock()
+	var a, ok = symtab.atoms[name]
+	symtab.RUnlock()
+
+	if ok {
+		return a
+	}
+
+	symtab.Lock()
+	defer symtab.Unlock()
+
+	if a, ok = symtab.atoms[name]; !ok {
+		a = &Atom{name: name}
+		symtab.atoms[name] = a
+	}
+
+	return a
+} // func Intern(name string) *Atom
+
+// MakeSymbol returns a Symbol of the given name without a source position.
+// The name is used as it is, it is not converted to upper case.
+func MakeSymbol(name string) Symbol {
+	return Symbol{Sym: name, atom: Intern(name)}
+} // func MakeSymbol(name string) Symbol
+
+// MakeUninterned returns a Symbol of the given name whose Atom is not in
+// the symbol table, so it is different from every other Symbol, including
+// those of the same name.
+func MakeUninterned(name string) Symbol {
+	return Symbol{Sym: name, atom: &Atom{name: name}}
+} // func MakeUninterned(name string) Symbol
+
+// Atom returns the interned Atom for the name of the Symbol. Symbols made
+// by the reader or MakeSymbol carry their Atom, for all others it is looked
+// up in the symbol table.
+func (s Symbol) Atom() *Atom {
+	if s.atom != nil && s.atom.name == s.Sym {
+		return s.atom
+	}
+
+	return Intern(s.Sym)
+} // func (s Symbol) Atom() *Atom
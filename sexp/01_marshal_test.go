@@ -0,0 +1,235 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/sexp/01_marshal_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 05:31:16 krylon>
+
+package sexp
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+type server struct {
+	ListenAddr string
+	Port       uint16
+	TLS        bool   `sexp:"use-tls"`
+	Comment    string `sexp:",omitempty"`
+	Secret     string `sexp:"-"`
+	HTTPProxy  *string
+	Tags       []string
+	Limits     map[string]int
+	secret     int
+}
+
+type config struct {
+	Name    string
+	Servers []server
+	Ratio   float64
+	Extra   any
+}
+
+// level implements Marshaler and Unmarshaler.
+type level int
+
+func (l level) MarshalSexp() (parser.LispValue, error) {
+	return parser.Symbol{Sym: strings.Repeat("*", int(l))}, nil
+} // func (l level) MarshalSexp() (parser.LispValue, error)
+
+func (l *level) UnmarshalSexp(v parser.LispValue) error {
+	var s, ok = v.(parser.Symbol)
+
+	if !ok || strings.Trim(s.Sym, "*") != "" {
+		return errors.New("level must be a symbol of asterisks")
+	}
+
+	*l = level(len(s.Sym))
+	return nil
+} // func (l *level) UnmarshalSexp(v parser.LispValue) error
+
+func TestLispName(t *testing.T) {
+	var cases = map[string]string{
+		"Name":       "NAME",
+		"ListenAddr": "LISTEN-ADDR",
+		"HTTPProxy":  "HTTP-PROXY",
+		"TLS":        "TLS",
+		"Port2":      "PORT2",
+		"snake_case": "SNAKE-CASE",
+	}
+
+	for ident, expected := range cases {
+		if name := lispName(ident); name != expected {
+			t.Errorf("lispName(%q) = %q, expected %q", ident, name, expected)
+		}
+	}
+} // func TestLispName(t *testing.T)
+
+type node struct {
+	Name string
+	Next *node
+}
+
+func TestMarshal(t *testing.T) {
+	var (
+		proxy  = "proxy:3128"
+		ring   = &node{Name: "a", Next: &node{Name: "b"}}
+		shared = &node{Name: "s"}
+		loop   = map[string]any{}
+		self   = []any{nil}
+	)
+
+	ring.Next.Next = ring
+	loop["self"] = loop
+	self[0] = self
+
+	type testCase struct {
+		val         any
+		style       StructStyle
+		expected    string
+		expectError bool
+	}
+
+	var cases = []testCase{
+		{val: nil, expected: "NIL"},
+		{val: true, expected: "T"},
+		{val: -3, expected: "-3"},
+		{val: 1.5, expected: "1.5"},
+		{val: "a \"quoted\" word", expected: `"a \"quoted\" word"`},
+		{val: []int{1, 2}, expected: "(1 2)"},
+		{val: []int(nil), expected: "NIL"},
+		{val: map[string]bool{"y": false, "x": true}, expected: `(("x" T) ("y" NIL))`},
+		{val: level(3), expected: "***"},
+		{val: []level{1, 2}, expected: "(* **)"},
+		{
+			val:      server{ListenAddr: "0.0.0.0", Port: 80, Secret: "x", HTTPProxy: &proxy, secret: 1},
+			expected: `(:LISTEN-ADDR "0.0.0.0" :PORT 80 :USE-TLS NIL :HTTP-PROXY "proxy:3128" :TAGS NIL :LIMITS NIL)`,
+		},
+		{
+			val:      server{Port: 443, TLS: true, Comment: "c", Tags: []string{"a"}},
+			style:    Alist,
+			expected: `((:LISTEN-ADDR "") (:PORT 443) (:USE-TLS T) (:COMMENT "c") (:HTTP-PROXY NIL) (:TAGS ("a")) (:LIMITS NIL))`,
+		},
+		{val: math.NaN(), expectError: true},
+		{val: math.Inf(1), expectError: true},
+		{val: uint64(math.MaxUint64), expectError: true},
+		{val: make(chan int), expectError: true},
+		{val: []any{1, func() {}}, expectError: true},
+		{val: ring, expectError: true},
+		{val: loop, expectError: true},
+		{val: self, expectError: true},
+		{val: []*node{shared, shared}, expected: `((:NAME "s" :NEXT NIL) (:NAME "s" :NEXT NIL))`},
+		{val: &node{Name: "x", Next: shared}, expected: `(:NAME "x" :NEXT (:NAME "s" :NEXT NIL))`},
+	}
+
+	for _, c := range cases {
+		var res, err = MarshalStyle(c.val, c.style)
+
+		if err != nil {
+			if !c.expectError {
+				t.Errorf("Error marshalling %#v: %s", c.val, err.Error())
+			} else if !errors.Is(err, ErrUnsupported) {
+				t.Errorf("Error marshalling %#v is not ErrUnsupported: %s", c.val, err.Error())
+			}
+		} else if c.expectError {
+			t.Errorf("Marshalling %#v should have failed, but returned %s", c.val, res)
+		} else if res.String() != c.expected {
+			t.Errorf("Unexpected result marshalling %#v:\nexpected %s\ngot      %s",
+				c.val,
+				c.expected,
+				res)
+		}
+	}
+} // func TestMarshal(t *testing.T)
+
+func TestUnmarshal(t *testing.T) {
+	var (
+		err   error
+		par   = parser.New()
+		proxy = "p"
+	)
+
+	type testCase struct {
+		src         string
+		target      any
+		expected    any
+		expectError bool
+	}
+
+	var cases = []testCase{
+		{src: "42", target: new(int), expected: 42},
+		{src: "300", target: new(uint8), expectError: true},
+		{src: "2", target: new(float32), expected: float32(2)},
+		{src: `"x"`, target: new(string), expected: "x"},
+		{src: "x", target: new(string), expectError: true},
+		{src: "t", target: new(bool), expected: true},
+		{src: "1", target: new(bool), expectError: true},
+		{src: "(1 2 3)", target: new([]int), expected: []int{1, 2, 3}},
+		{src: "()", target: new([]int), expected: []int(nil)},
+		{src: "(1 2)", target: new([3]int), expectError: true},
+		{src: `(("a" 1))`, target: new(map[string]int), expected: map[string]int{"a": 1}},
+		{src: `(1 "a" foo nil t 2.5)`, target: new(any), expected: []any{int64(1), "a", "FOO", nil, true, 2.5}},
+		{src: "(1 2)", target: new(parser.LispValue), expected: parser.MakeList(parser.Integer{Int: 1}, parser.Integer{Int: 2})},
+		{src: "5", target: new(*int), expected: func() *int { var i = 5; return &i }()},
+		{src: "**", target: new(level), expected: level(2)},
+		{src: "(* ***)", target: new([]level), expected: []level{1, 3}},
+		{src: "+", target: new(level), expectError: true},
+		{
+			src:      `(:listen-addr "::1" :port 8080 :use-tls t :http-proxy "p" :tags ("a" "b") :limits (("conn" 10)) :unknown 1)`,
+			target:   new(server),
+			expected: server{ListenAddr: "::1", Port: 8080, TLS: true, HTTPProxy: &proxy, Tags: []string{"a", "b"}, Limits: map[string]int{"conn": 10}},
+		},
+		{
+			src:      `((:port 1) (:comment "alist"))`,
+			target:   new(server),
+			expected: server{Port: 1, Comment: "alist"},
+		},
+		{
+			src:    `(:name "cfg" :ratio 0.5 :extra (1 2) :servers ((:port 1) ((:port 2))))`,
+			target: new(config),
+			expected: config{
+				Name:    "cfg",
+				Ratio:   0.5,
+				Extra:   []any{int64(1), int64(2)},
+				Servers: []server{{Port: 1}, {Port: 2}},
+			},
+		},
+		{src: `(:port)`, target: new(server), expectError: true},
+		{src: `(port 1)`, target: new(server), expectError: true},
+		{src: `((:port 1 2))`, target: new(server), expectError: true},
+		{src: `(:port "80")`, target: new(server), expectError: true},
+	}
+
+	for _, c := range cases {
+		var val *parser.LispValue
+
+		if val, err = par.ParseString("test", c.src); err != nil {
+			t.Fatalf("Cannot parse %q: %s", c.src, err.Error())
+		} else if err = Unmarshal(*val, c.target); err != nil {
+			if !c.expectError {
+				t.Errorf("Error unmarshalling %s into %T: %s", c.src, c.target, err.Error())
+			}
+			continue
+		} else if c.expectError {
+			t.Errorf("Unmarshalling %s into %T should have failed", c.src, c.target)
+			continue
+		}
+
+		var res = reflect.ValueOf(c.target).Elem().Interface()
+
+		if lv, ok := res.(parser.LispValue); ok && !lv.Equal(c.expected.(parser.LispValue)) {
+			t.Errorf("Unmarshalling %s returned %s, expected %s", c.src, lv, c.expected)
+		} else if !ok && !reflect.DeepEqual(res, c.expected) {
+			t.Errorf("Unmarshalling %s returned %#v, expected %#v", c.src, res, c.expected)
+		}
+	}
+
+	if err = Unmarshal(parser.Integer{Int: 1}, 1); err == nil {
+		t.Error("Unmarshal should reject a target that is not a pointer")
+	}
+} // func TestUnmarshal(t *testing.T)
@@ -0,0 +1,142 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/sexp/02_stream_test.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 05:52:40 krylon>
+
+package sexp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	var (
+		buf   bytes.Buffer
+		proxy = "proxy \"quoted\"\n"
+		enc   = NewEncoder(&buf)
+		cfgs  = []config{
+			{
+				Name:  "first",
+				Ratio: 1e21,
+				Servers: []server{
+					{ListenAddr: "localhost", Port: 80, HTTPProxy: &proxy},
+					{Port: 443, TLS: true, Tags: []string{"a;b", "(c)"}, Limits: map[string]int{"x": -1}},
+				},
+				Extra: "extra",
+			},
+			{Name: "second"},
+		}
+	)
+
+	for i, cfg := range cfgs {
+		if i == 1 {
+			enc.SetStructStyle(Alist)
+		}
+
+		if err := enc.Encode(cfg); err != nil {
+			t.Fatalf("Error encoding config %d: %s", i, err.Error())
+		}
+	}
+
+	t.Logf("Encoded configs:\n%s", buf.String())
+
+	var dec = NewDecoder(&buf)
+
+	for i, expected := range cfgs {
+		var cfg config
+
+		if err := dec.Decode(&cfg); err != nil {
+			t.Fatalf("Error decoding config %d: %s", i, err.Error())
+		} else if !reflect.DeepEqual(cfg, expected) {
+			t.Errorf("Config %d was not decoded correctly:\nexpected %#v\ngot      %#v",
+				i,
+				expected,
+				cfg)
+		}
+	}
+
+	var cfg config
+
+	if err := dec.Decode(&cfg); err != io.EOF {
+		t.Errorf("Expected io.EOF at end of stream, got %v", err)
+	}
+} // func TestRoundTrip(t *testing.T)
+
+func TestDecoderForms(t *testing.T) {
+	const src = `
+; A comment before the first form
+42 foo "a string with ) and ; in it"(1 2)
+'quoted ` + "`(a ,b ,@c)" + ` (nested (list "\"x\"")) ; trailing comment
+#h((a . 1)) (a;comment
+b) last`
+
+	var (
+		dec      = NewDecoder(strings.NewReader(src))
+		expected = []string{
+			"42",
+			"FOO",
+			`"a string with ) and ; in it"`,
+			"(1 2)",
+			"(QUOTE QUOTED)",
+			"(QUASIQUOTE (A (UNQUOTE B) (UNQUOTE-SPLICING C)))",
+			`(NESTED (LIST "\"x\""))`,
+			"#H(:TEST EQL (A . 1))",
+			"(A B)",
+			"LAST",
+		}
+	)
+
+	for i, exp := range expected {
+		var val, err = dec.ReadValue()
+
+		if err != nil {
+			t.Fatalf("Error reading form %d: %s", i, err.Error())
+		} else if val.String() != exp {
+			t.Errorf("Form %d: expected %s, got %s", i, exp, val)
+		}
+	}
+
+	if _, err := dec.ReadValue(); err != io.EOF {
+		t.Errorf("Expected io.EOF at end of input, got %v", err)
+	}
+
+	var broken = []string{`(1 2`, `"open`, `)`, `'`}
+
+	for _, b := range broken {
+		if _, err := NewDecoder(strings.NewReader(b)).ReadValue(); err == nil || err == io.EOF {
+			t.Errorf("Reading %q should have failed, got %v", b, err)
+		}
+	}
+} // func TestDecoderForms(t *testing.T)
+
+func TestDecoderUnknownFields(t *testing.T) {
+	var (
+		srv server
+		dec = NewDecoder(strings.NewReader(`(:port 1 :bogus 2)`))
+	)
+
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&srv); !errors.Is(err, ErrMismatch) {
+		t.Errorf("Unknown field was not rejected: %v", err)
+	}
+} // func TestDecoderUnknownFields(t *testing.T)
+
+func TestEncoderFloats(t *testing.T) {
+	var (
+		buf bytes.Buffer
+		enc = NewEncoder(&buf)
+	)
+
+	if err := enc.Encode([]float64{1, 0}); err != nil {
+		t.Errorf("Error encoding floats: %s", err.Error())
+	} else if buf.String() != "(1.0 0.0)\n" {
+		t.Errorf("Unexpected encoding of floats: %q", buf.String())
+	}
+} // func TestEncoderFloats(t *testing.T)
@@ -0,0 +1,310 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/sexp/decode.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 04:47:12 krylon>
+
+package sexp
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// Unmarshal decodes the LispValue val and stores the result in the value v
+// points to, which must be a non-nil pointer. Keywords in struct encodings
+// that do not match a field are ignored.
+func Unmarshal(val parser.LispValue, v any) error {
+	var c Converter
+
+	return c.Unmarshal(val, v)
+} // func Unmarshal(val parser.LispValue, v any) error
+
+type decoder struct {
+	disallowUnknown bool
+}
+
+func (d *decoder) unmarshal(val parser.LispValue, v any) error {
+	var rv = reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("Cannot unmarshal into %T, need a non-nil pointer", v)
+	}
+
+	return d.decode(val, rv.Elem())
+} // func (d *decoder) unmarshal(val parser.LispValue, v any) error
+
+func (d *decoder) decode(val parser.LispValue, rv reflect.Value) error {
+	var t = rv.Type()
+
+	if t.Kind() == reflect.Pointer {
+		if parser.IsNil(val) {
+			rv.Set(reflect.Zero(t))
+			return nil
+		} else if rv.IsNil() {
+			rv.Set(reflect.New(t.Elem()))
+		}
+
+		return d.decode(val, rv.Elem())
+	} else if rv.CanAddr() && rv.Addr().Type().Implements(unmarshalerType) {
+		return rv.Addr().Interface().(Unmarshaler).UnmarshalSexp(val)
+	} else if t.Kind() == reflect.Interface && t.NumMethod() == 0 {
+		if nat := natural(val); nat != nil {
+			rv.Set(reflect.ValueOf(nat))
+		} else {
+			rv.Set(reflect.Zero(t))
+		}
+
+		return nil
+	} else if val != nil && reflect.TypeOf(val).AssignableTo(t) {
+		rv.Set(reflect.ValueOf(val))
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		switch s, _ := val.(parser.Symbol); s.Sym {
+		case "T":
+			rv.SetBool(true)
+			return nil
+		case "NIL":
+			rv.SetBool(false)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, ok := val.(parser.Integer); ok {
+			if rv.OverflowInt(i.Int) {
+				return fmt.Errorf("%w: %d does not fit into a %s", ErrMismatch, i.Int, t)
+			}
+
+			rv.SetInt(i.Int)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if i, ok := val.(parser.Integer); ok {
+			if i.Int < 0 || rv.OverflowUint(uint64(i.Int)) {
+				return fmt.Errorf("%w: %d does not fit into a %s", ErrMismatch, i.Int, t)
+			}
+
+			rv.SetUint(uint64(i.Int))
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		switch n := val.(type) {
+		case parser.Float:
+			rv.SetFloat(n.Flt)
+			return nil
+		case parser.Integer:
+			rv.SetFloat(float64(n.Int))
+			return nil
+		}
+	case reflect.String:
+		if s, ok := val.(parser.String); ok {
+			rv.SetString(s.Str)
+			return nil
+		}
+	case reflect.Slice:
+		if items, ok := parser.ListItems(val); ok {
+			if len(items) == 0 {
+				rv.Set(reflect.Zero(t))
+				return nil
+			}
+
+			rv.Set(reflect.MakeSlice(t, len(items), len(items)))
+			return d.decodeItems(items, rv)
+		}
+	case reflect.Array:
+		if items, ok := parser.ListItems(val); ok {
+			if len(items) != t.Len() {
+				return fmt.Errorf("%w: Cannot decode a List of %d elements into a %s",
+					ErrMismatch,
+					len(items),
+					t)
+			}
+
+			return d.decodeItems(items, rv)
+		}
+	case reflect.Map:
+		if items, ok := parser.ListItems(val); ok {
+			if len(items) == 0 {
+				rv.Set(reflect.Zero(t))
+				return nil
+			}
+
+			return d.decodeMap(items, rv)
+		}
+	case reflect.Struct:
+		if items, ok := parser.ListItems(val); ok {
+			return d.decodeStruct(items, rv)
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupported, t)
+	}
+
+	return fmt.Errorf("%w: Cannot decode %s into a %s",
+		ErrMismatch,
+		describe(val),
+		t)
+} // func (d *decoder) decode(val parser.LispValue, rv reflect.Value) error
+
+// decodeItems decodes the elements of a List into the slice or array rv,
+// which must have the same length.
+func (d *decoder) decodeItems(items []parser.LispValue, rv reflect.Value) error {
+	for i, item := range items {
+		if err := d.decode(item, rv.Index(i)); err != nil {
+			return fmt.Errorf("Element %d: %w", i, err)
+		}
+	}
+
+	return nil
+} // func (d *decoder) decodeItems(items []parser.LispValue, rv reflect.Value) error
+
+// decodeMap decodes a List of (key value) Lists into the map rv. If rv is
+// nil, a new map is allocated, otherwise the entries are added to it.
+func (d *decoder) decodeMap(entries []parser.LispValue, rv reflect.Value) error {
+	var t = rv.Type()
+
+	if rv.IsNil() {
+		rv.Set(reflect.MakeMapWithSize(t, len(entries)))
+	}
+
+	for _, e := range entries {
+		var (
+			err    error
+			kv, ok = parser.ListItems(e)
+			key    = reflect.New(t.Key()).Elem()
+			val    = reflect.New(t.Elem()).Elem()
+		)
+
+		if !ok || len(kv) != 2 {
+			return fmt.Errorf("%w: Map entry must be a List of key and value, not %s",
+				ErrMismatch,
+				e)
+		} else if err = d.decode(kv[0], key); err != nil {
+			return fmt.Errorf("Map key %s: %w", kv[0], err)
+		} else if err = d.decode(kv[1], val); err != nil {
+			return fmt.Errorf("Map value for %s: %w", kv[0], err)
+		}
+
+		rv.SetMapIndex(key, val)
+	}
+
+	return nil
+} // func (d *decoder) decodeMap(entries []parser.LispValue, rv reflect.Value) error
+
+// decodeStruct decodes a property or association list into the struct rv.
+// Fields that do not appear in the list keep their value.
+func (d *decoder) decodeStruct(items []parser.LispValue, rv reflect.Value) error {
+	var (
+		t       = rv.Type()
+		fields  = make(map[string]int)
+		keys    []parser.LispValue
+		vals    []parser.LispValue
+		isAlist bool
+	)
+
+	for _, f := range structFields(t) {
+		fields[f.keyword] = f.index
+	}
+
+	if len(items) > 0 {
+		_, isAlist = items[0].(*parser.ConsCell)
+	}
+
+	if isAlist {
+		for _, item := range items {
+			var pair, ok = parser.ListItems(item)
+
+			if !ok || len(pair) != 2 {
+				return fmt.Errorf("%w: Entry of association list for %s must be a List of key and value, not %s",
+					ErrMismatch,
+					t,
+					item)
+			}
+
+			keys = append(keys, pair[0])
+			vals = append(vals, pair[1])
+		}
+	} else if len(items)%2 != 0 {
+		return fmt.Errorf("%w: Property list for %s has an odd number of elements",
+			ErrMismatch,
+			t)
+	} else {
+		for i := 0; i < len(items); i += 2 {
+			keys = append(keys, items[i])
+			vals = append(vals, items[i+1])
+		}
+	}
+
+	for i, k := range keys {
+		var (
+			kw, ok = k.(parser.Symbol)
+			idx    int
+		)
+
+		if !ok || !kw.IsKeyword() {
+			return fmt.Errorf("%w: Field names of %s must be keywords, not %s",
+				ErrMismatch,
+				t,
+				k)
+		} else if idx, ok = fields[kw.Sym]; !ok {
+			if d.disallowUnknown {
+				return fmt.Errorf("%w: %s has no field %s", ErrMismatch, t, kw)
+			}
+
+			continue
+		} else if err := d.decode(vals[i], rv.Field(idx)); err != nil {
+			return fmt.Errorf("Field %s: %w", t.Field(idx).Name, err)
+		}
+	}
+
+	return nil
+} // func (d *decoder) decodeStruct(items []parser.LispValue, rv reflect.Value) error
+
+// natural returns the Go value a LispValue is decoded to when the target
+// is of type any: int64, float64, string, bool, []any or nil. Symbols other
+// than T and NIL are decoded to their name.
+func natural(v parser.LispValue) any {
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case parser.Integer:
+		return x.Int
+	case parser.Float:
+		return x.Flt
+	case parser.String:
+		return x.Str
+	case parser.Symbol:
+		switch x.Sym {
+		case "NIL":
+			return nil
+		case "T":
+			return true
+		}
+		return x.Sym
+	default:
+		var items, ok = parser.ListItems(v)
+
+		if !ok {
+			return v
+		}
+
+		var res = make([]any, len(items))
+
+		for i, item := range items {
+			res[i] = natural(item)
+		}
+
+		return res
+	}
+} // func natural(v parser.LispValue) any
+
+func describe(v parser.LispValue) string {
+	if v == nil {
+		return "nil"
+	}
+
+	return fmt.Sprintf("%s %s", v.Type(), v)
+} // func describe(v parser.LispValue) string
@@ -0,0 +1,236 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/sexp/encode.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 04:19:53 krylon>
+
+package sexp
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// Marshal returns the LispValue v is encoded as, with structs encoded as
+// property lists.
+func Marshal(v any) (parser.LispValue, error) {
+	return MarshalStyle(v, Plist)
+} // func Marshal(v any) (parser.LispValue, error)
+
+// MarshalStyle returns the LispValue v is encoded as, with structs encoded
+// in the given style.
+func MarshalStyle(v any, style StructStyle) (parser.LispValue, error) {
+	var c = Converter{Style: style}
+
+	return c.Marshal(v)
+} // func MarshalStyle(v any, style StructStyle) (parser.LispValue, error)
+
+type encoder struct {
+	style   StructStyle
+	errorFn func(error) parser.LispValue
+	visited map[visit]bool
+}
+
+// visit identifies a pointer, map or slice the encoder is currently
+// descending into. Slices are distinguished by their length as well, since
+// a slice and a prefix of it share the same pointer.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// enter records that the encoder descends into rv, which must be a
+// pointer, map or slice. It fails if rv is already being encoded further
+// up, i.e. if the value contains itself.
+func (e *encoder) enter(rv reflect.Value) (visit, error) {
+	var v = visit{ptr: rv.Pointer(), typ: rv.Type()}
+
+	if rv.Kind() == reflect.Slice {
+		v.len = rv.Len()
+	}
+
+	if e.visited == nil {
+		e.visited = make(map[visit]bool)
+	} else if e.visited[v] {
+		return v, fmt.Errorf("%w: encountered a cycle via %s",
+			ErrUnsupported,
+			v.typ)
+	}
+
+	e.visited[v] = true
+	return v, nil
+} // func (e *encoder) enter(rv reflect.Value) (visit, error)
+
+func (e *encoder) encode(rv reflect.Value) (parser.LispValue, error) {
+	if !rv.IsValid() {
+		return parser.MakeSymbol("NIL"), nil
+	}
+
+	var t = rv.Type()
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return parser.MakeSymbol("NIL"), nil
+		}
+	}
+
+	if t.Implements(marshalerType) {
+		return rv.Interface().(Marshaler).MarshalSexp()
+	} else if t.Implements(lispValueType) {
+		return rv.Interface().(parser.LispValue), nil
+	} else if e.errorFn != nil && t.Implements(errorType) {
+		return e.errorFn(rv.Interface().(error)), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		if rv.Bool() {
+			return parser.MakeSymbol("T"), nil
+		}
+		return parser.MakeSymbol("NIL"), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return parser.Integer{Int: rv.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n := rv.Uint(); n <= math.MaxInt64 {
+			return parser.Integer{Int: int64(n)}, nil
+		}
+
+		return nil, fmt.Errorf("%w: %d does not fit into an Integer",
+			ErrUnsupported,
+			rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		if f := rv.Float(); !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return parser.Float{Flt: f}, nil
+		}
+
+		return nil, fmt.Errorf("%w: %g cannot be encoded",
+			ErrUnsupported,
+			rv.Float())
+	case reflect.String:
+		return parser.MakeString(rv.String()), nil
+	case reflect.Interface:
+		return e.encode(rv.Elem())
+	case reflect.Pointer:
+		var v, err = e.enter(rv)
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer delete(e.visited, v)
+		return e.encode(rv.Elem())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Len() > 0 {
+			var v, err = e.enter(rv)
+
+			if err != nil {
+				return nil, err
+			}
+
+			defer delete(e.visited, v)
+		}
+
+		var items = make([]parser.LispValue, rv.Len())
+
+		for i := range items {
+			var err error
+
+			if items[i], err = e.encode(rv.Index(i)); err != nil {
+				return nil, fmt.Errorf("Element %d: %w", i, err)
+			}
+		}
+
+		return parser.MakeList(items...), nil
+	case reflect.Map:
+		var v, err = e.enter(rv)
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer delete(e.visited, v)
+		return e.encodeMap(rv)
+	case reflect.Struct:
+		return e.encodeStruct(rv)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, t)
+	}
+} // func (e *encoder) encode(rv reflect.Value) (parser.LispValue, error)
+
+// encodeMap encodes a map as a List of (key value) Lists, sorted by the
+// printed representation of the keys.
+func (e *encoder) encodeMap(rv reflect.Value) (parser.LispValue, error) {
+	var (
+		entries = make([]parser.LispValue, 0, rv.Len())
+		iter    = rv.MapRange()
+	)
+
+	for iter.Next() {
+		var (
+			err  error
+			k, v parser.LispValue
+		)
+
+		if k, err = e.encode(iter.Key()); err != nil {
+			return nil, fmt.Errorf("Map key: %w", err)
+		} else if v, err = e.encode(iter.Value()); err != nil {
+			return nil, fmt.Errorf("Map value for %s: %w", k, err)
+		}
+
+		entries = append(entries, parser.MakeList(k, v))
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].(*parser.ConsCell).Car.String() < entries[j].(*parser.ConsCell).Car.String()
+	})
+
+	return parser.MakeList(entries...), nil
+} // func (e *encoder) encodeMap(rv reflect.Value) (parser.LispValue, error)
+
+// encodeStruct encodes a struct as a property or association list,
+// depending on the style of the encoder.
+func (e *encoder) encodeStruct(rv reflect.Value) (parser.LispValue, error) {
+	var (
+		fields = structFields(rv.Type())
+		items  = make([]parser.LispValue, 0, 2*len(fields))
+	)
+
+	for _, f := range fields {
+		var (
+			err error
+			val parser.LispValue
+			fv  = rv.Field(f.index)
+			kw  = parser.Symbol{Sym: f.keyword}
+		)
+
+		if f.omitEmpty && isEmpty(fv) {
+			continue
+		} else if val, err = e.encode(fv); err != nil {
+			return nil, fmt.Errorf("Field %s: %w", rv.Type().Field(f.index).Name, err)
+		}
+
+		if e.style == Alist {
+			items = append(items, parser.MakeList(kw, val))
+		} else {
+			items = append(items, kw, val)
+		}
+	}
+
+	return parser.MakeList(items...), nil
+} // func (e *encoder) encodeStruct(rv reflect.Value) (parser.LispValue, error)
+
+// isEmpty returns true if rv is a zero value for the purpose of omitempty.
+func isEmpty(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	default:
+		return rv.IsZero()
+	}
+} // func isEmpty(rv reflect.Value) bool
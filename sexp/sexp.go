@@ -0,0 +1,191 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/sexp/sexp.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 04:02:37 krylon>
+
+// Package sexp encodes Go values as S-expressions and decodes them again,
+// in the manner of encoding/json. Marshal and Unmarshal convert between Go
+// values and LispValues, Encoder and Decoder read and write the textual
+// form on streams.
+//
+// Go values are encoded like this:
+//
+//	bool                  T or NIL
+//	signed/unsigned ints  Integer
+//	float32, float64      Float (NaN and infinities cannot be encoded)
+//	string                String
+//	slices, arrays        List, a nil slice is NIL
+//	maps                  List of (key value) Lists, sorted by key
+//	structs               property list (:field value ...), or association
+//	                      list ((:field value) ...) with the Alist style
+//	pointers, interfaces  the value they point to, nil is NIL
+//
+// Values that contain themselves, through a pointer, map or slice, cannot
+// be encoded and make Marshal fail with ErrUnsupported.
+//
+// By default, the keyword of a struct field is derived from its name, e.g.
+// ListenAddr becomes :LISTEN-ADDR. It can be set with a struct tag like
+// `sexp:"name"`. The option omitempty, as in `sexp:"name,omitempty"` or
+// `sexp:",omitempty"`, skips fields with a zero value, and a tag of `sexp:"-"`
+// skips the field altogether. When decoding, structs are accepted in either
+// style.
+//
+// Marshal and Unmarshal use the default settings, a Converter can be
+// configured to encode errors, e.g. as conditions, and to reject unknown
+// struct fields. The interop package uses one to pass values between Go
+// functions and Lisp.
+package sexp
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/blicero/krylisp/parser"
+)
+
+// ErrUnsupported indicates a Go value or type that has no representation
+// as an S-expression.
+var ErrUnsupported = errors.New("Unsupported type")
+
+// ErrMismatch indicates a LispValue that cannot be decoded into the Go
+// value it was supposed to be stored in.
+var ErrMismatch = errors.New("Value does not match type")
+
+// StructStyle determines how structs are encoded.
+type StructStyle uint8
+
+// Plist encodes structs as property lists, Alist as association lists.
+const (
+	Plist StructStyle = iota
+	Alist
+)
+
+// Marshaler is implemented by types that encode themselves.
+type Marshaler interface {
+	MarshalSexp() (parser.LispValue, error)
+}
+
+// Unmarshaler is implemented by types that decode themselves. The receiver
+// must be a pointer.
+type Unmarshaler interface {
+	UnmarshalSexp(parser.LispValue) error
+}
+
+var (
+	lispValueType   = reflect.TypeOf((*parser.LispValue)(nil)).Elem()
+	errorType       = reflect.TypeOf((*error)(nil)).Elem()
+	marshalerType   = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+)
+
+// Converter converts between Go values and LispValues with settings that
+// differ from the defaults of Marshal and Unmarshal. The zero value uses
+// the defaults.
+type Converter struct {
+	// Style is the style structs are encoded in.
+	Style StructStyle
+	// Error, if not nil, encodes Go values that implement error.
+	// Otherwise, they are encoded like any other value.
+	Error func(error) parser.LispValue
+	// DisallowUnknownFields makes decoding fail on keywords in struct
+	// encodings that do not match any field.
+	DisallowUnknownFields bool
+}
+
+// Marshal returns the LispValue v is encoded as.
+func (c *Converter) Marshal(v any) (parser.LispValue, error) {
+	var e = encoder{style: c.Style, errorFn: c.Error}
+
+	return e.encode(reflect.ValueOf(v))
+} // func (c *Converter) Marshal(v any) (parser.LispValue, error)
+
+// Unmarshal decodes the LispValue val and stores the result in the value v
+// points to, which must be a non-nil pointer.
+func (c *Converter) Unmarshal(val parser.LispValue, v any) error {
+	var d = decoder{disallowUnknown: c.DisallowUnknownFields}
+
+	return d.unmarshal(val, v)
+} // func (c *Converter) Unmarshal(val parser.LispValue, v any) error
+
+// field describes an encoded struct field.
+type field struct {
+	index     int
+	keyword   string
+	omitEmpty bool
+}
+
+// fieldCache maps struct types to their encoded fields, so the tags of a
+// type are only parsed once.
+var fieldCache sync.Map
+
+// structFields returns the encoded fields of the struct type t.
+func structFields(t reflect.Type) []field {
+	if fields, ok := fieldCache.Load(t); ok {
+		return fields.([]field)
+	}
+
+	var fields = make([]field, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		var (
+			f          = t.Field(i)
+			name, opts string
+		)
+
+		if !f.IsExported() {
+			continue
+		} else if tag, ok := f.Tag.Lookup("sexp"); ok {
+			if tag == "-" {
+				continue
+			}
+
+			name, opts, _ = strings.Cut(tag, ",")
+		}
+
+		if name == "" {
+			name = lispName(f.Name)
+		}
+
+		fields = append(fields, field{
+			index:     i,
+			keyword:   ":" + strings.ToUpper(name),
+			omitEmpty: opts == "omitempty",
+		})
+	}
+
+	fieldCache.Store(t, fields)
+	return fields
+} // func structFields(t reflect.Type) []field
+
+// lispName converts a Go identifier to the customary style of Lisp symbols,
+// e.g. ListenAddr to LISTEN-ADDR and HTTPServer to HTTP-SERVER.
+func lispName(ident string) string {
+	var (
+		b     strings.Builder
+		runes = []rune(ident)
+	)
+
+	for i, r := range runes {
+		if r == '_' {
+			b.WriteRune('-')
+			continue
+		} else if i > 0 && unicode.IsUpper(r) && runes[i-1] != '_' {
+			var (
+				prevLower = unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+				nextLower = i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			)
+
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				b.WriteRune('-')
+			}
+		}
+
+		b.WriteRune(unicode.ToUpper(r))
+	}
+
+	return b.String()
+} // func lispName(ident string) string
@@ -0,0 +1,263 @@
+// /home/krylon/go/src/github.com/blicero/krylisp/sexp/stream.go
+// -*- mode: go; coding: utf-8; -*-
+// Created on 18. 10. 2026 by Benjamin Walkenhorst
+// (c) 2026 Benjamin Walkenhorst
+// Time-stamp: <2026-10-18 05:10:28 krylon>
+
+package sexp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/alecthomas/participle/v2"
+	"github.com/blicero/krylisp/parser"
+)
+
+// Encoder writes S-expressions to a stream, one per line.
+type Encoder struct {
+	w     io.Writer
+	style StructStyle
+}
+
+// NewEncoder returns an Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+} // func NewEncoder(w io.Writer) *Encoder
+
+// SetStructStyle sets the style the Encoder uses for structs. The default
+// is Plist.
+func (e *Encoder) SetStructStyle(style StructStyle) {
+	e.style = style
+} // func (e *Encoder) SetStructStyle(style StructStyle)
+
+// Encode writes the S-expression for v to the stream, followed by a
+// newline.
+func (e *Encoder) Encode(v any) error {
+	var (
+		err error
+		val parser.LispValue
+		buf []byte
+	)
+
+	if val, err = MarshalStyle(v, e.style); err != nil {
+		return err
+	} else if buf, err = appendValue(buf, val); err != nil {
+		return err
+	}
+
+	buf = append(buf, '\n')
+
+	_, err = e.w.Write(buf)
+	return err
+} // func (e *Encoder) Encode(v any) error
+
+// appendValue appends the textual form of v to buf. Unlike the String
+// methods of the LispValues, it escapes Strings, so the result can be read
+// back. Values that have no readable representation, such as Functions,
+// are an error.
+func appendValue(buf []byte, v parser.LispValue) ([]byte, error) {
+	switch x := v.(type) {
+	case parser.Symbol:
+		return append(buf, x.Sym...), nil
+	case parser.Integer:
+		return strconv.AppendInt(buf, x.Int, 10), nil
+	case parser.Float:
+		if math.IsNaN(x.Flt) || math.IsInf(x.Flt, 0) {
+			return nil, fmt.Errorf("%w: %s cannot be encoded", ErrUnsupported, x)
+		}
+
+		return append(buf, x.String()...), nil
+	case parser.String:
+		return strconv.AppendQuote(buf, x.Str), nil
+	case *parser.ConsCell:
+		var err error
+
+		buf = append(buf, '(')
+
+		for c := x; c != nil; c = c.Rest() {
+			if c != x {
+				buf = append(buf, ' ')
+			}
+
+			if buf, err = appendValue(buf, c.Car); err != nil {
+				return nil, err
+			} else if c.Rest() == nil && !parser.IsNil(c.Cdr) {
+				buf = append(buf, " . "...)
+
+				if buf, err = appendValue(buf, c.Cdr); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		return append(buf, ')'), nil
+	default:
+		return nil, fmt.Errorf("%w: %T has no readable representation",
+			ErrUnsupported,
+			v)
+	}
+} // func appendValue(buf []byte, v parser.LispValue) ([]byte, error)
+
+// Decoder reads S-expressions from a stream.
+type Decoder struct {
+	r   *bufio.Reader
+	par *participle.Parser[parser.LispValue]
+	d   decoder
+}
+
+// NewDecoder returns a Decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		r:   bufio.NewReader(r),
+		par: parser.New(),
+	}
+} // func NewDecoder(r io.Reader) *Decoder
+
+// DisallowUnknownFields makes the Decoder return an error when a struct
+// encoding contains a keyword that does not match any field.
+func (d *Decoder) DisallowUnknownFields() {
+	d.d.disallowUnknown = true
+} // func (d *Decoder) DisallowUnknownFields()
+
+// Decode reads the next S-expression from the stream and stores it in the
+// value v points to, see Unmarshal. At the end of the stream, it returns
+// io.EOF.
+func (d *Decoder) Decode(v any) error {
+	var val, err = d.ReadValue()
+
+	if err != nil {
+		return err
+	}
+
+	return d.d.unmarshal(val, v)
+} // func (d *Decoder) Decode(v any) error
+
+// ReadValue reads the next S-expression from the stream. At the end of the
+// stream, it returns io.EOF.
+func (d *Decoder) ReadValue() (parser.LispValue, error) {
+	var (
+		err  error
+		src  string
+		form *parser.LispValue
+	)
+
+	if src, err = d.readForm(); err != nil {
+		return nil, err
+	} else if form, err = d.par.ParseString("stream", src); err != nil {
+		return nil, err
+	}
+
+	return *form, nil
+} // func (d *Decoder) ReadValue() (parser.LispValue, error)
+
+// readForm reads the source text of the next top-level form from the
+// stream. It only reads as far as necessary, so it works on streams that
+// stay open, like network connections.
+func (d *Decoder) readForm() (string, error) {
+	var (
+		b                          strings.Builder
+		depth                      int
+		atom, inString, escaped    bool
+		inComment, started, quoted bool
+	)
+
+	for {
+		var r, _, err = d.r.ReadRune()
+
+		if errors.Is(err, io.EOF) {
+			if inString || depth > 0 || (quoted && !atom) {
+				return "", io.ErrUnexpectedEOF
+			} else if !started {
+				return "", io.EOF
+			}
+
+			return b.String(), nil
+		} else if err != nil {
+			return "", err
+		}
+
+		if inComment {
+			// The newline that ends the comment still separates the
+			// elements around it.
+			if inComment = r != '\n'; !inComment && depth > 0 {
+				b.WriteRune(r)
+			}
+
+			continue
+		} else if inString {
+			b.WriteRune(r)
+
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == '"':
+				inString = false
+
+				if depth == 0 {
+					return b.String(), nil
+				}
+			}
+
+			continue
+		}
+
+		// At the top level, an atom ends at the first character that
+		// cannot be part of it, except for #H, which opens a HashTable
+		// literal.
+		if atom && r == '(' && strings.EqualFold(b.String(), "#h") {
+			atom = false
+		} else if atom && (unicode.IsSpace(r) || strings.ContainsRune(`;"()'`+"`,", r)) {
+			_ = d.r.UnreadRune()
+			return b.String(), nil
+		}
+
+		switch {
+		case r == ';':
+			inComment = true
+		case unicode.IsSpace(r):
+			if depth > 0 {
+				b.WriteRune(r)
+			}
+		case r == '"':
+			b.WriteRune(r)
+			inString, started = true, true
+		case r == '(':
+			b.WriteRune(r)
+			depth++
+			started = true
+		case r == ')':
+			if depth == 0 {
+				return "", errors.New("Unexpected ) in input")
+			}
+
+			b.WriteRune(r)
+
+			if depth--; depth == 0 {
+				return b.String(), nil
+			}
+		case strings.ContainsRune("'`,", r):
+			b.WriteRune(r)
+			started = true
+
+			if depth == 0 {
+				quoted = true
+			}
+		default:
+			b.WriteRune(r)
+			started = true
+
+			if depth == 0 {
+				atom = true
+			}
+		}
+	}
+} // func (d *Decoder) readForm() (string, error)
@@ -18,6 +18,8 @@ const (
 	Integer
 	Float
 	ConsCell
-	List
 	Function
+	Macro
+	Condition
+	HashTable
 )